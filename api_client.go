@@ -1,31 +1,94 @@
 package soracom
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // APIClient provides an access to SORACOM REST API
+//
+// An APIClient is safe for concurrent use by multiple goroutines once it has been created.
+// Credentials can be replaced (e.g. by Auth or automatic token refresh) while other API calls are in flight;
+// each call uses an API key and a token from the same authentication.
 type APIClient struct {
-	httpClient *http.Client
-	APIKey     string
-	Token      string
-	OperatorID string
-	endpoint   string
-	verbose    bool
+	httpClient           *http.Client
+	roundTrip            RoundTripFunc
+	endpoint             string
+	verbose              int32
+	logger               Logger
+	retryPolicy          *RetryPolicy
+	rateLimits           *RateLimits
+	tokenTimeout         time.Duration
+	tokenRefreshMargin   time.Duration
+	sandbox              bool
+	lenientDecoding      bool
+	validateTransitions  bool
+	validateSpeedClasses bool
+
+	creds credentialHolder
+
+	// authMu serializes re-authentication
+	authMu sync.Mutex
 }
 
 // APIClientOptions holds options for creating an APIClient
 type APIClientOptions struct {
+	// Endpoint overrides the API endpoint derived from CoverageType and Sandbox
 	Endpoint string
 	Client   *http.Client
+
+	// CoverageType selects the API endpoint for the coverage type. Defaults to Japan coverage.
+	CoverageType CoverageType
+
+	// Sandbox selects the API sandbox endpoint. Sandbox-only APIs can be called only if the client is connected to a sandbox.
+	// Clients with Endpoint pointing to a known sandbox host are considered to be connected to a sandbox as well,
+	// while clients with Endpoint pointing to a production host are never considered to be connected to a sandbox.
+	Sandbox bool
+
+	// RetryPolicy enables retrying failed API calls. API calls are not retried if nil.
+	RetryPolicy *RetryPolicy
+
+	// RateLimits limits the rate of API calls. It can be shared among APIClients for the same operator (see SharedRateLimits).
+	RateLimits *RateLimits
+
+	// Middlewares intercept every HTTP request and response. The first middleware is the outermost one.
+	Middlewares []Middleware
+
+	// Logger receives a log record for every HTTP request. Nothing is logged if nil unless verbose output is enabled.
+	Logger Logger
+
+	// LenientDecoding makes list APIs return the items which could be decoded along with DecodeErrors for the others.
+	// By default, list APIs fail with a *DecodeError if any item cannot be decoded.
+	LenientDecoding bool
+
+	// ValidateTransitions makes the methods changing the status of a subscriber, such as ActivateSubscriber and TerminateSubscriber,
+	// get the subscriber first and fail with an error matching ErrInvalidStateTransition without calling the API if the change is not possible.
+	ValidateTransitions bool
+
+	// ValidateSpeedClasses makes UpdateSubscriberSpeedClass get the subscriber first and fail with an error matching ErrUnsupportedSpeedClass
+	// without calling the API if the speed class is not available for the subscription of the subscriber.
+	ValidateSpeedClasses bool
+
+	// TokenTimeout is the lifetime of API tokens issued by Auth functions. Defaults to 24 hours.
+	TokenTimeout time.Duration
+
+	// TokenRefreshMargin is how long before expiry an API token is refreshed. Defaults to 5 minutes.
+	TokenRefreshMargin time.Duration
 }
 
+const (
+	defaultTokenTimeout       = 24 * time.Hour
+	defaultTokenRefreshMargin = 5 * time.Minute
+)
+
 // NewAPIClient creates an instance of APIClient
 func NewAPIClient(options *APIClientOptions) *APIClient {
 	hc := http.DefaultClient
@@ -34,18 +97,49 @@ func NewAPIClient(options *APIClientOptions) *APIClient {
 		hc = options.Client
 	}
 
-	var endpoint = "https://api.soracom.io"
-	if options != nil && options.Endpoint != "" {
-		endpoint = options.Endpoint
+	var endpoint = APIEndpoint(CoverageTypeJapan, false)
+	var sandbox bool
+	if options != nil {
+		sandbox = options.Sandbox
+		endpoint = APIEndpoint(options.CoverageType, options.Sandbox)
+		if options.Endpoint != "" {
+			endpoint = options.Endpoint
+			sandbox = (sandbox || isSandboxEndpoint(endpoint)) && !isProductionEndpoint(endpoint)
+		}
+	}
+
+	var retryPolicy *RetryPolicy
+	var rateLimits *RateLimits
+	var middlewares []Middleware
+	var logger Logger
+	tokenTimeout := defaultTokenTimeout
+	tokenRefreshMargin := defaultTokenRefreshMargin
+	if options != nil {
+		retryPolicy = options.RetryPolicy
+		rateLimits = options.RateLimits
+		middlewares = options.Middlewares
+		logger = options.Logger
+		if options.TokenTimeout > 0 {
+			tokenTimeout = options.TokenTimeout
+		}
+		if options.TokenRefreshMargin > 0 {
+			tokenRefreshMargin = options.TokenRefreshMargin
+		}
 	}
 
 	return &APIClient{
-		httpClient: hc,
-		APIKey:     "",
-		Token:      "",
-		OperatorID: "",
-		endpoint:   endpoint,
-		verbose:    false,
+		httpClient:           hc,
+		roundTrip:            chainMiddlewares(hc.Do, middlewares),
+		endpoint:             endpoint,
+		logger:               logger,
+		retryPolicy:          retryPolicy,
+		rateLimits:           rateLimits,
+		tokenTimeout:         tokenTimeout,
+		tokenRefreshMargin:   tokenRefreshMargin,
+		sandbox:              sandbox,
+		lenientDecoding:      options != nil && options.LenientDecoding,
+		validateTransitions:  options != nil && options.ValidateTransitions,
+		validateSpeedClasses: options != nil && options.ValidateSpeedClasses,
 	}
 }
 
@@ -57,119 +151,358 @@ type apiParams struct {
 	body        string
 }
 
-func (ac *APIClient) callAPI(params *apiParams) (*http.Response, error) {
-	url := ac.endpoint + params.path
-	if params.query != "" {
-		url += "?" + params.query
+func (ac *APIClient) callAPI(ctx context.Context, params *apiParams) (*http.Response, error) {
+	if strings.HasPrefix(params.path, sandboxPathPrefix) && !ac.sandbox {
+		return nil, ErrNotSandbox
 	}
-	//fmt.Printf("url == %v\n", url)
-	req, err := http.NewRequest(params.method, url, strings.NewReader(params.body))
+
+	if params.path == authPath {
+		return ac.doCallAPI(ctx, params)
+	}
+
+	err := ac.refreshTokenIfNeeded(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	if params.contentType != "" {
-		req.Header.Set("Content-Type", params.contentType)
+	token := ac.creds.load().token
+	res, err := ac.doCallAPI(ctx, params)
+	if isAuthError(err) && ac.canReauth() {
+		err = ac.reauth(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		return ac.doCallAPI(ctx, params)
 	}
+	return res, err
+}
 
-	req.Header.Set("X-Soracom-API-Key", ac.APIKey)
-	req.Header.Set("X-Soracom-Token", ac.Token)
+func (ac *APIClient) doCallAPI(ctx context.Context, params *apiParams) (*http.Response, error) {
+	res, err := doWithRetry(ctx, ac.retryPolicy, params.method, func() (*http.Response, error) {
+		return ac.sendRequest(ctx, params)
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	if ac.verbose {
-		dumpHTTPRequest(req)
+	if res.StatusCode >= http.StatusBadRequest {
+		defer res.Body.Close()
+		return nil, NewAPIError(res)
 	}
 
-	res, err := ac.httpClient.Do(req)
+	return res, nil
+}
+
+func (ac *APIClient) sendRequest(ctx context.Context, params *apiParams) (*http.Response, error) {
+	err := ac.rateLimits.wait(ctx, params.method, params.path)
 	if err != nil {
 		return nil, err
 	}
 
-	if ac.verbose {
-		dumpHTTPResponse(res)
-		fmt.Println("==========")
+	url := ac.endpoint + params.path
+	if params.query != "" {
+		url += "?" + params.query
+	}
+	//fmt.Printf("url == %v\n", url)
+	req, err := http.NewRequestWithContext(ctx, params.method, url, strings.NewReader(params.body))
+	if err != nil {
+		return nil, err
 	}
 
-	if res.StatusCode >= http.StatusBadRequest {
-		defer res.Body.Close()
-		return nil, NewAPIError(res)
+	if params.contentType != "" {
+		req.Header.Set("Content-Type", params.contentType)
 	}
 
-	return res, nil
+	creds := ac.creds.load()
+	req.Header.Set("X-Soracom-API-Key", creds.apiKey)
+	req.Header.Set("X-Soracom-Token", creds.token)
+
+	return logRoundTrip(ctx, ac.logger, atomic.LoadInt32(&ac.verbose) != 0, ac.roundTrip, req, params.body)
+}
+
+// IsSandbox reports whether the client is connected to an API sandbox
+func (ac *APIClient) IsSandbox() bool {
+	return ac.sandbox
 }
 
-// SetVerbose sets if verbose output is enabled or not
+// SetVerbose sets if verbose output is enabled or not.
+// Verbose output logs redacted wire dumps of requests and responses to the Logger given in options, or to stdout if no Logger is given.
+// It is safe to call SetVerbose while API calls are in flight.
 func (ac *APIClient) SetVerbose(verbose bool) {
-	ac.verbose = verbose
+	atomic.StoreInt32(&ac.verbose, boolToInt32(verbose))
+}
+
+// boolToInt32 converts b for the verbose flags stored with atomic.StoreInt32
+func boolToInt32(b bool) int32 {
+	if b {
+		return 1
+	}
+	return 0
 }
 
 // Auth does the authentication process. Gets an API key and an API Token
 func (ac *APIClient) Auth(email, password string) error {
+	return ac.AuthWithContext(context.Background(), email, password)
+}
+
+// AuthWithContext is the context-aware version of Auth.
+func (ac *APIClient) AuthWithContext(ctx context.Context, email, password string) error {
 	body := &AuthRequest{
 		Email:    email,
 		Password: password,
 	}
-	return ac.auth(body)
+	return ac.auth(ctx, body)
 }
 
 // AuthWithAuthKey does the authentication process with auth key. Gets an API key and an API Token
 func (ac *APIClient) AuthWithAuthKey(authKeyID, authKey string) error {
+	return ac.AuthWithAuthKeyWithContext(context.Background(), authKeyID, authKey)
+}
+
+// AuthWithAuthKeyWithContext is the context-aware version of AuthWithAuthKey.
+func (ac *APIClient) AuthWithAuthKeyWithContext(ctx context.Context, authKeyID, authKey string) error {
 	body := &AuthRequest{
 		AuthKeyID: authKeyID,
 		AuthKey:   authKey,
 	}
-	return ac.auth(body)
+	return ac.auth(ctx, body)
+}
+
+// AuthWithSAMUser does the authentication process as a SAM user of the operator. Gets an API key and an API Token
+func (ac *APIClient) AuthWithSAMUser(operatorID, userName, password string) error {
+	return ac.AuthWithSAMUserWithContext(context.Background(), operatorID, userName, password)
+}
+
+// AuthWithSAMUserWithContext is the context-aware version of AuthWithSAMUser.
+func (ac *APIClient) AuthWithSAMUserWithContext(ctx context.Context, operatorID, userName, password string) error {
+	body := &AuthRequest{
+		OperatorID: operatorID,
+		UserName:   userName,
+		Password:   password,
+	}
+	return ac.auth(ctx, body)
+}
+
+// AuthWithMFA does the authentication process of a root user with a one-time code for multi-factor authentication.
+// The client cannot re-authenticate by itself after the token expires because the code cannot be reused, though it keeps refreshing the token while it is valid.
+func (ac *APIClient) AuthWithMFA(email, password, mfaOTPCode string) error {
+	return ac.AuthWithMFAWithContext(context.Background(), email, password, mfaOTPCode)
+}
+
+// AuthWithMFAWithContext is the context-aware version of AuthWithMFA.
+func (ac *APIClient) AuthWithMFAWithContext(ctx context.Context, email, password, mfaOTPCode string) error {
+	body := &AuthRequest{
+		Email:      email,
+		Password:   password,
+		MFAOTPCode: mfaOTPCode,
+	}
+	return ac.auth(ctx, body)
+}
+
+// AuthWithSAMUserAndMFA does the authentication process as a SAM user with a one-time code for multi-factor authentication.
+// See AuthWithMFA for the limitation on re-authentication.
+func (ac *APIClient) AuthWithSAMUserAndMFA(operatorID, userName, password, mfaOTPCode string) error {
+	return ac.AuthWithSAMUserAndMFAWithContext(context.Background(), operatorID, userName, password, mfaOTPCode)
+}
+
+// AuthWithSAMUserAndMFAWithContext is the context-aware version of AuthWithSAMUserAndMFA.
+func (ac *APIClient) AuthWithSAMUserAndMFAWithContext(ctx context.Context, operatorID, userName, password, mfaOTPCode string) error {
+	body := &AuthRequest{
+		OperatorID: operatorID,
+		UserName:   userName,
+		Password:   password,
+		MFAOTPCode: mfaOTPCode,
+	}
+	return ac.auth(ctx, body)
 }
 
-func (ac *APIClient) auth(body *AuthRequest) error {
-	body.TokenTimeoutSeconds = 24 * 60 * 60
+func (ac *APIClient) auth(ctx context.Context, body *AuthRequest) error {
+	body.TokenTimeoutSeconds = int(ac.tokenTimeout / time.Second)
 	params := &apiParams{
 		method:      "POST",
-		path:        "/v1/auth",
+		path:        authPath,
 		contentType: "application/json",
 		body:        body.JSON(),
 	}
 
-	resp, err := ac.callAPI(params)
+	issuedAt := time.Now()
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	respBody := parseAuthResponse(resp)
-	ac.APIKey = respBody.APIKey
-	ac.Token = respBody.Token
-	ac.OperatorID = respBody.OperatorID
+	respBody, err := parseAuthResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	// one-time codes cannot be replayed to authenticate again
+	authRequest := body
+	if body.MFAOTPCode != "" {
+		authRequest = nil
+	}
+
+	ac.creds.update(func(authState) authState {
+		return authState{
+			apiKey:      respBody.APIKey,
+			token:       respBody.Token,
+			operatorID:  respBody.OperatorID,
+			userName:    respBody.UserName,
+			expiresAt:   issuedAt.Add(ac.tokenTimeout),
+			authRequest: authRequest,
+		}
+	})
 
 	return nil
 }
 
+// SwitchUser switches the client to the SAM user userName of the operator operatorID.
+// The switch is repeated automatically whenever the client re-authenticates.
+func (ac *APIClient) SwitchUser(operatorID, userName string) error {
+	return ac.SwitchUserWithContext(context.Background(), operatorID, userName)
+}
+
+// SwitchUserWithContext is the context-aware version of SwitchUser.
+func (ac *APIClient) SwitchUserWithContext(ctx context.Context, operatorID, userName string) error {
+	err := ac.refreshTokenIfNeeded(ctx)
+	if err != nil {
+		return err
+	}
+
+	return ac.switchUser(ctx, &switchUserRequest{
+		OperatorID: operatorID,
+		UserName:   userName,
+	})
+}
+
+func (ac *APIClient) switchUser(ctx context.Context, body *switchUserRequest) error {
+	body.TokenTimeoutSeconds = int(ac.tokenTimeout / time.Second)
+	params := &apiParams{
+		method:      "POST",
+		path:        "/v1/auth/switch_user",
+		contentType: "application/json",
+		body:        body.JSON(),
+	}
+
+	issuedAt := time.Now()
+	resp, err := ac.doCallAPI(ctx, params)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := parseAuthResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	ac.creds.update(func(s authState) authState {
+		return authState{
+			apiKey:      respBody.APIKey,
+			token:       respBody.Token,
+			operatorID:  respBody.OperatorID,
+			userName:    respBody.UserName,
+			expiresAt:   issuedAt.Add(ac.tokenTimeout),
+			authRequest: s.authRequest,
+			switchUser:  body,
+		}
+	})
+
+	return nil
+}
+
+// APIKey returns the API key currently in use
+func (ac *APIClient) APIKey() string {
+	return ac.creds.load().apiKey
+}
+
+// Token returns the API token currently in use
+func (ac *APIClient) Token() string {
+	return ac.creds.load().token
+}
+
+// OperatorID returns the ID of the operator the client has authenticated as
+func (ac *APIClient) OperatorID() string {
+	return ac.creds.load().operatorID
+}
+
+// currentOperatorID returns the operator ID for paths which embed it, authenticating first if the client has not authenticated yet
+func (ac *APIClient) currentOperatorID(ctx context.Context) (string, error) {
+	err := ac.refreshTokenIfNeeded(ctx)
+	if err != nil {
+		return "", err
+	}
+	return ac.OperatorID(), nil
+}
+
+// UserName returns the name of the SAM user the client has authenticated as. It is empty for the root user.
+func (ac *APIClient) UserName() string {
+	return ac.creds.load().userName
+}
+
+// SetAuthInfo sets an API key, an API token and an operator ID obtained elsewhere (e.g. from another APIClient).
+// The client does not refresh a token set this way because it does not know how to authenticate.
+func (ac *APIClient) SetAuthInfo(apiKey, token, operatorID string) {
+	ac.creds.update(func(authState) authState {
+		return authState{
+			apiKey:     apiKey,
+			token:      token,
+			operatorID: operatorID,
+		}
+	})
+}
+
 // GenerateAPIToken generates an API token
 func (ac *APIClient) GenerateAPIToken(timeout int) (string, error) {
+	return ac.GenerateAPITokenWithContext(context.Background(), timeout)
+}
+
+// GenerateAPITokenWithContext is the context-aware version of GenerateAPIToken.
+func (ac *APIClient) GenerateAPITokenWithContext(ctx context.Context, timeout int) (string, error) {
+	operatorID, err := ac.currentOperatorID(ctx)
+	if err != nil {
+		return "", err
+	}
+
 	params := &apiParams{
 		method:      "POST",
-		path:        "/v1/operators/" + ac.OperatorID + "/token",
+		path:        "/v1/operators/" + operatorID + "/token",
 		contentType: "application/json",
 		body:        (&generateAPITokenRequest{Timeout: timeout}).JSON(),
 	}
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return "", err
 	}
 	defer resp.Body.Close()
 
-	respBody := parseGenerateAPITokenResponse(resp)
+	respBody, err := parseGenerateAPITokenResponse(resp)
+	if err != nil {
+		return "", err
+	}
 	return respBody.Token, nil
 }
 
 // UpdatePassword updates operator's password
 func (ac *APIClient) UpdatePassword(currentPassword, newPassword string) error {
+	return ac.UpdatePasswordWithContext(context.Background(), currentPassword, newPassword)
+}
+
+// UpdatePasswordWithContext is the context-aware version of UpdatePassword.
+func (ac *APIClient) UpdatePasswordWithContext(ctx context.Context, currentPassword, newPassword string) error {
+	operatorID, err := ac.currentOperatorID(ctx)
+	if err != nil {
+		return err
+	}
+
 	params := &apiParams{
 		method:      "POST",
-		path:        "/v1/operators/" + ac.OperatorID + "/password",
+		path:        "/v1/operators/" + operatorID + "/password",
 		contentType: "application/json",
 		body:        (&updatePasswordRequest{CurrentPassword: currentPassword, NewPassword: newPassword}).JSON(),
 	}
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return err
 	}
@@ -180,37 +513,60 @@ func (ac *APIClient) UpdatePassword(currentPassword, newPassword string) error {
 
 // GetSupportToken retrieves a token for accessing to the support site
 func (ac *APIClient) GetSupportToken() (string, error) {
+	return ac.GetSupportTokenWithContext(context.Background())
+}
+
+// GetSupportTokenWithContext is the context-aware version of GetSupportToken.
+func (ac *APIClient) GetSupportTokenWithContext(ctx context.Context) (string, error) {
+	operatorID, err := ac.currentOperatorID(ctx)
+	if err != nil {
+		return "", err
+	}
+
 	params := &apiParams{
 		method:      "POST",
-		path:        "/v1/operators/" + ac.OperatorID + "/support/token",
+		path:        "/v1/operators/" + operatorID + "/support/token",
 		contentType: "application/json",
 		body:        "{}",
 	}
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return "", err
 	}
 	defer resp.Body.Close()
 
-	respBody := parseGetSupportTokenResponse(resp)
+	respBody, err := parseGetSupportTokenResponse(resp)
+	if err != nil {
+		return "", err
+	}
 	return respBody.Token, nil
 }
 
 // CreateOperator sends a request to create an operator with the specified email & password. (This function exists because it predates the addition of the coverageTypes API parameter. The newer CreateOperatorWithRequest() supports all of the API parameters.)
 func (ac *APIClient) CreateOperator(email, password string) error {
+	return ac.CreateOperatorWithContext(context.Background(), email, password)
+}
+
+// CreateOperatorWithContext is the context-aware version of CreateOperator.
+func (ac *APIClient) CreateOperatorWithContext(ctx context.Context, email, password string) error {
 	req := CreateOperatorRequest{Email: email, Password: password}
-	return ac.CreateOperatorWithRequest(req)
+	return ac.CreateOperatorWithRequestWithContext(ctx, req)
 }
 
 // CreateOperatorWithRequest sends a request to create an operator, whose email, password, and coverageTypes properties are defined by req.
 func (ac *APIClient) CreateOperatorWithRequest(req CreateOperatorRequest) error {
+	return ac.CreateOperatorWithRequestWithContext(context.Background(), req)
+}
+
+// CreateOperatorWithRequestWithContext is the context-aware version of CreateOperatorWithRequest.
+func (ac *APIClient) CreateOperatorWithRequestWithContext(ctx context.Context, req CreateOperatorRequest) error {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/operators",
 		contentType: "application/json",
 		body:        (&req).JSON(),
 	}
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return err
 	}
@@ -221,13 +577,18 @@ func (ac *APIClient) CreateOperatorWithRequest(req CreateOperatorRequest) error
 
 // VerifyOperator sends a token to complete an operator creation process.
 func (ac *APIClient) VerifyOperator(token string) error {
+	return ac.VerifyOperatorWithContext(context.Background(), token)
+}
+
+// VerifyOperatorWithContext is the context-aware version of VerifyOperator.
+func (ac *APIClient) VerifyOperatorWithContext(ctx context.Context, token string) error {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/operators/verify",
 		contentType: "application/json",
 		body:        (&verifyOperatorRequest{Token: token}).JSON(),
 	}
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return err
 	}
@@ -238,24 +599,32 @@ func (ac *APIClient) VerifyOperator(token string) error {
 
 // GetOperator gets information about an operator specifed by operatorID.
 func (ac *APIClient) GetOperator(operatorID string) (*Operator, error) {
+	return ac.GetOperatorWithContext(context.Background(), operatorID)
+}
+
+// GetOperatorWithContext is the context-aware version of GetOperator.
+func (ac *APIClient) GetOperatorWithContext(ctx context.Context, operatorID string) (*Operator, error) {
 	params := &apiParams{
 		method: "GET",
 		path:   "/v1/operators/" + operatorID,
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	operator := parseOperator(resp)
-
-	return operator, nil
+	return parseOperator(resp)
 }
 
 // ListSubscribers lists subscribers for the operator
 func (ac *APIClient) ListSubscribers(options *ListSubscribersOptions) ([]Subscriber, *PaginationKeys, error) {
+	return ac.ListSubscribersWithContext(context.Background(), options)
+}
+
+// ListSubscribersWithContext is the context-aware version of ListSubscribers.
+func (ac *APIClient) ListSubscribersWithContext(ctx context.Context, options *ListSubscribersOptions) ([]Subscriber, *PaginationKeys, error) {
 	params := &apiParams{
 		method: "GET",
 		path:   "/v1/subscribers",
@@ -264,22 +633,22 @@ func (ac *APIClient) ListSubscribers(options *ListSubscribersOptions) ([]Subscri
 		params.query = options.String()
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, nil, err
 	}
 	defer resp.Body.Close()
 
-	subscribers, paginationKeys, err := parseListSubscribersResponse(resp)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return subscribers, paginationKeys, nil
+	return parseListSubscribersResponse(resp, ac.lenientDecoding)
 }
 
 // RegisterSubscriber registers a subscriber.
 func (ac *APIClient) RegisterSubscriber(imsi string, regOptions RegisterSubscriberOptions) (*Subscriber, error) {
+	return ac.RegisterSubscriberWithContext(context.Background(), imsi, regOptions)
+}
+
+// RegisterSubscriberWithContext is the context-aware version of RegisterSubscriber.
+func (ac *APIClient) RegisterSubscriberWithContext(ctx context.Context, imsi string, regOptions RegisterSubscriberOptions) (*Subscriber, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/subscribers/" + imsi + "/register",
@@ -287,37 +656,48 @@ func (ac *APIClient) RegisterSubscriber(imsi string, regOptions RegisterSubscrib
 		body:        regOptions.JSON(),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
-
-	return subscriber, nil
+	return parseSubscriber(resp)
 }
 
 // GetSubscriber gets information about a subscriber specifed by imsi.
 func (ac *APIClient) GetSubscriber(imsi string) (*Subscriber, error) {
+	return ac.GetSubscriberWithContext(context.Background(), imsi)
+}
+
+// GetSubscriberWithContext is the context-aware version of GetSubscriber.
+func (ac *APIClient) GetSubscriberWithContext(ctx context.Context, imsi string) (*Subscriber, error) {
 	params := &apiParams{
 		method: "GET",
 		path:   "/v1/subscribers/" + imsi,
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
-
-	return subscriber, nil
+	return parseSubscriber(resp)
 }
 
 // UpdateSubscriberSpeedClass updates speed class of a subscriber.
-func (ac *APIClient) UpdateSubscriberSpeedClass(imsi, speedClass string) (*Subscriber, error) {
+func (ac *APIClient) UpdateSubscriberSpeedClass(imsi string, speedClass SpeedClass) (*Subscriber, error) {
+	return ac.UpdateSubscriberSpeedClassWithContext(context.Background(), imsi, speedClass)
+}
+
+// UpdateSubscriberSpeedClassWithContext is the context-aware version of UpdateSubscriberSpeedClass.
+func (ac *APIClient) UpdateSubscriberSpeedClassWithContext(ctx context.Context, imsi string, speedClass SpeedClass) (*Subscriber, error) {
+	err := ac.validateSpeedClass(ctx, imsi, speedClass)
+	if err != nil {
+		return nil, err
+	}
+
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/subscribers/" + imsi + "/update_speed_class",
@@ -325,19 +705,30 @@ func (ac *APIClient) UpdateSubscriberSpeedClass(imsi, speedClass string) (*Subsc
 		body:        (&updateSpeedClassRequest{SpeedClass: speedClass}).JSON(),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
-
-	return subscriber, nil
+	return parseSubscriber(resp)
 }
 
 // ActivateSubscriber activates a subscriber.
 func (ac *APIClient) ActivateSubscriber(imsi string) (*Subscriber, error) {
+	return ac.ActivateSubscriberWithContext(context.Background(), imsi)
+}
+
+// ActivateSubscriberWithContext is the context-aware version of ActivateSubscriber.
+func (ac *APIClient) ActivateSubscriberWithContext(ctx context.Context, imsi string) (*Subscriber, error) {
+	err := ac.validateTransition(ctx, imsi, SubscriberStatusActive)
+	if err != nil {
+		return nil, err
+	}
+	return ac.activateSubscriber(ctx, imsi)
+}
+
+func (ac *APIClient) activateSubscriber(ctx context.Context, imsi string) (*Subscriber, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/subscribers/" + imsi + "/activate",
@@ -345,19 +736,30 @@ func (ac *APIClient) ActivateSubscriber(imsi string) (*Subscriber, error) {
 		body:        "{}",
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
-
-	return subscriber, nil
+	return parseSubscriber(resp)
 }
 
 // DeactivateSubscriber deactivates a subscriber.
 func (ac *APIClient) DeactivateSubscriber(imsi string) (*Subscriber, error) {
+	return ac.DeactivateSubscriberWithContext(context.Background(), imsi)
+}
+
+// DeactivateSubscriberWithContext is the context-aware version of DeactivateSubscriber.
+func (ac *APIClient) DeactivateSubscriberWithContext(ctx context.Context, imsi string) (*Subscriber, error) {
+	err := ac.validateTransition(ctx, imsi, SubscriberStatusInactive)
+	if err != nil {
+		return nil, err
+	}
+	return ac.deactivateSubscriber(ctx, imsi)
+}
+
+func (ac *APIClient) deactivateSubscriber(ctx context.Context, imsi string) (*Subscriber, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/subscribers/" + imsi + "/deactivate",
@@ -365,19 +767,30 @@ func (ac *APIClient) DeactivateSubscriber(imsi string) (*Subscriber, error) {
 		body:        "{}",
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
-
-	return subscriber, nil
+	return parseSubscriber(resp)
 }
 
 // TerminateSubscriber terminates a subscriber.
 func (ac *APIClient) TerminateSubscriber(imsi string) (*Subscriber, error) {
+	return ac.TerminateSubscriberWithContext(context.Background(), imsi)
+}
+
+// TerminateSubscriberWithContext is the context-aware version of TerminateSubscriber.
+func (ac *APIClient) TerminateSubscriberWithContext(ctx context.Context, imsi string) (*Subscriber, error) {
+	err := ac.validateTransition(ctx, imsi, SubscriberStatusTerminated)
+	if err != nil {
+		return nil, err
+	}
+	return ac.terminateSubscriber(ctx, imsi)
+}
+
+func (ac *APIClient) terminateSubscriber(ctx context.Context, imsi string) (*Subscriber, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/subscribers/" + imsi + "/terminate",
@@ -385,19 +798,22 @@ func (ac *APIClient) TerminateSubscriber(imsi string) (*Subscriber, error) {
 		body:        "{}",
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
-
-	return subscriber, nil
+	return parseSubscriber(resp)
 }
 
 // EnableSubscriberTermination enables termination of a subscriber.
 func (ac *APIClient) EnableSubscriberTermination(imsi string) (*Subscriber, error) {
+	return ac.EnableSubscriberTerminationWithContext(context.Background(), imsi)
+}
+
+// EnableSubscriberTerminationWithContext is the context-aware version of EnableSubscriberTermination.
+func (ac *APIClient) EnableSubscriberTerminationWithContext(ctx context.Context, imsi string) (*Subscriber, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/subscribers/" + imsi + "/enable_termination",
@@ -405,19 +821,22 @@ func (ac *APIClient) EnableSubscriberTermination(imsi string) (*Subscriber, erro
 		body:        "{}",
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
-
-	return subscriber, nil
+	return parseSubscriber(resp)
 }
 
 // DisableSubscriberTermination disables termination of a subscriber.
 func (ac *APIClient) DisableSubscriberTermination(imsi string) (*Subscriber, error) {
+	return ac.DisableSubscriberTerminationWithContext(context.Background(), imsi)
+}
+
+// DisableSubscriberTerminationWithContext is the context-aware version of DisableSubscriberTermination.
+func (ac *APIClient) DisableSubscriberTerminationWithContext(ctx context.Context, imsi string) (*Subscriber, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/subscribers/" + imsi + "/disable_termination",
@@ -425,19 +844,22 @@ func (ac *APIClient) DisableSubscriberTermination(imsi string) (*Subscriber, err
 		body:        "{}",
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
-
-	return subscriber, nil
+	return parseSubscriber(resp)
 }
 
 // ListSessionEvents get session events
 func (ac *APIClient) ListSessionEvents(imsi string, options *ListSessionEventsOption) ([]SessionEvent, *PaginationKeys, error) {
+	return ac.ListSessionEventsWithContext(context.Background(), imsi, options)
+}
+
+// ListSessionEventsWithContext is the context-aware version of ListSessionEvents.
+func (ac *APIClient) ListSessionEventsWithContext(ctx context.Context, imsi string, options *ListSessionEventsOption) ([]SessionEvent, *PaginationKeys, error) {
 	params := &apiParams{
 		method:      "GET",
 		path:        fmt.Sprintf("/v1/subscribers/%s/events/sessions", imsi),
@@ -446,22 +868,30 @@ func (ac *APIClient) ListSessionEvents(imsi string, options *ListSessionEventsOp
 
 	params.query = options.queryString().Encode()
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, nil, err
 	}
 	defer resp.Body.Close()
 
-	sessionEvents, paginationKeys, err := parseListSessionEvents(resp)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return sessionEvents, paginationKeys, err
+	return parseListSessionEvents(resp, ac.lenientDecoding)
 }
 
 // Suspend suspend a subscriber.
 func (ac *APIClient) Suspend(imsi string) (*Subscriber, error) {
+	return ac.SuspendWithContext(context.Background(), imsi)
+}
+
+// SuspendWithContext is the context-aware version of Suspend.
+func (ac *APIClient) SuspendWithContext(ctx context.Context, imsi string) (*Subscriber, error) {
+	err := ac.validateTransition(ctx, imsi, SubscriberStatusSuspended)
+	if err != nil {
+		return nil, err
+	}
+	return ac.suspend(ctx, imsi)
+}
+
+func (ac *APIClient) suspend(ctx context.Context, imsi string) (*Subscriber, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/subscribers/" + imsi + "/suspend",
@@ -469,19 +899,30 @@ func (ac *APIClient) Suspend(imsi string) (*Subscriber, error) {
 		body:        "{}",
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
-
-	return subscriber, nil
+	return parseSubscriber(resp)
 }
 
 // SetToStandby set to standby a subscriber.
 func (ac *APIClient) SetToStandby(imsi string) (*Subscriber, error) {
+	return ac.SetToStandbyWithContext(context.Background(), imsi)
+}
+
+// SetToStandbyWithContext is the context-aware version of SetToStandby.
+func (ac *APIClient) SetToStandbyWithContext(ctx context.Context, imsi string) (*Subscriber, error) {
+	err := ac.validateTransition(ctx, imsi, SubscriberStatusStandby)
+	if err != nil {
+		return nil, err
+	}
+	return ac.setToStandby(ctx, imsi)
+}
+
+func (ac *APIClient) setToStandby(ctx context.Context, imsi string) (*Subscriber, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/subscribers/" + imsi + "/set_to_standby",
@@ -489,19 +930,22 @@ func (ac *APIClient) SetToStandby(imsi string) (*Subscriber, error) {
 		body:        "{}",
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
-
-	return subscriber, nil
+	return parseSubscriber(resp)
 }
 
 // SetSubscriberExpiredAt sets expiration time of a subscriber.
 func (ac *APIClient) SetSubscriberExpiredAt(imsi string, expiryTime time.Time) (*Subscriber, error) {
+	return ac.SetSubscriberExpiredAtWithContext(context.Background(), imsi, expiryTime)
+}
+
+// SetSubscriberExpiredAtWithContext is the context-aware version of SetSubscriberExpiredAt.
+func (ac *APIClient) SetSubscriberExpiredAtWithContext(ctx context.Context, imsi string, expiryTime time.Time) (*Subscriber, error) {
 	ts := &TimestampMilli{Time: expiryTime}
 	req := &setExpiredAtRequest{
 		ExpiredAt: fmt.Sprint(ts.UnixMilli()),
@@ -513,19 +957,22 @@ func (ac *APIClient) SetSubscriberExpiredAt(imsi string, expiryTime time.Time) (
 		body:        req.JSON(),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
-
-	return subscriber, nil
+	return parseSubscriber(resp)
 }
 
 // UnsetSubscriberExpiredAt unsets expiration time of a subscriber.
 func (ac *APIClient) UnsetSubscriberExpiredAt(imsi string) (*Subscriber, error) {
+	return ac.UnsetSubscriberExpiredAtWithContext(context.Background(), imsi)
+}
+
+// UnsetSubscriberExpiredAtWithContext is the context-aware version of UnsetSubscriberExpiredAt.
+func (ac *APIClient) UnsetSubscriberExpiredAtWithContext(ctx context.Context, imsi string) (*Subscriber, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/subscribers/" + imsi + "/unset_expiry_time",
@@ -533,19 +980,22 @@ func (ac *APIClient) UnsetSubscriberExpiredAt(imsi string) (*Subscriber, error)
 		body:        "{}",
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
-
-	return subscriber, nil
+	return parseSubscriber(resp)
 }
 
 // SetSubscriberGroup sets a group of a subscriber.
 func (ac *APIClient) SetSubscriberGroup(imsi, groupID string) (*Subscriber, error) {
+	return ac.SetSubscriberGroupWithContext(context.Background(), imsi, groupID)
+}
+
+// SetSubscriberGroupWithContext is the context-aware version of SetSubscriberGroup.
+func (ac *APIClient) SetSubscriberGroupWithContext(ctx context.Context, imsi, groupID string) (*Subscriber, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/subscribers/" + imsi + "/set_group",
@@ -553,19 +1003,22 @@ func (ac *APIClient) SetSubscriberGroup(imsi, groupID string) (*Subscriber, erro
 		body:        (&setSubscriberGroupRequest{GroupID: groupID}).JSON(),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
-
-	return subscriber, nil
+	return parseSubscriber(resp)
 }
 
 // UnsetSubscriberGroup unsets group of a subscriber.
 func (ac *APIClient) UnsetSubscriberGroup(imsi string) (*Subscriber, error) {
+	return ac.UnsetSubscriberGroupWithContext(context.Background(), imsi)
+}
+
+// UnsetSubscriberGroupWithContext is the context-aware version of UnsetSubscriberGroup.
+func (ac *APIClient) UnsetSubscriberGroupWithContext(ctx context.Context, imsi string) (*Subscriber, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/subscribers/" + imsi + "/unset_group",
@@ -573,19 +1026,112 @@ func (ac *APIClient) UnsetSubscriberGroup(imsi string) (*Subscriber, error) {
 		body:        "{}",
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return parseSubscriber(resp)
+}
+
+// SetSubscriberIMEILock locks a subscriber to the device with the IMEI. If imei is empty, the subscriber is locked to the IMEI of the device in the current session.
+func (ac *APIClient) SetSubscriberIMEILock(imsi, imei string) (*Subscriber, error) {
+	return ac.SetSubscriberIMEILockWithContext(context.Background(), imsi, imei)
+}
+
+// SetSubscriberIMEILockWithContext is the context-aware version of SetSubscriberIMEILock.
+func (ac *APIClient) SetSubscriberIMEILockWithContext(ctx context.Context, imsi, imei string) (*Subscriber, error) {
+	params := &apiParams{
+		method:      "POST",
+		path:        "/v1/subscribers/" + imsi + "/set_imei_lock",
+		contentType: "application/json",
+		body:        (&setIMEILockRequest{IMEI: imei}).JSON(),
+	}
+
+	resp, err := ac.callAPI(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return parseSubscriber(resp)
+}
+
+// UnsetSubscriberIMEILock removes the IMEI lock of a subscriber.
+func (ac *APIClient) UnsetSubscriberIMEILock(imsi string) (*Subscriber, error) {
+	return ac.UnsetSubscriberIMEILockWithContext(context.Background(), imsi)
+}
+
+// UnsetSubscriberIMEILockWithContext is the context-aware version of UnsetSubscriberIMEILock.
+func (ac *APIClient) UnsetSubscriberIMEILockWithContext(ctx context.Context, imsi string) (*Subscriber, error) {
+	params := &apiParams{
+		method:      "POST",
+		path:        "/v1/subscribers/" + imsi + "/unset_imei_lock",
+		contentType: "application/json",
+		body:        "{}",
+	}
+
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
+	return parseSubscriber(resp)
+}
+
+// SendSMSToSubscriber sends an SMS message to a subscriber and returns the ID of the message.
+// The text is validated before sending; the error matches ErrSMSTooLong if it does not fit in a message.
+func (ac *APIClient) SendSMSToSubscriber(imsi, text string, encoding SMSEncoding) (string, error) {
+	return ac.SendSMSToSubscriberWithContext(context.Background(), imsi, text, encoding)
+}
+
+// SendSMSToSubscriberWithContext is the context-aware version of SendSMSToSubscriber.
+func (ac *APIClient) SendSMSToSubscriberWithContext(ctx context.Context, imsi, text string, encoding SMSEncoding) (string, error) {
+	return ac.sendSMS(ctx, "/v1/subscribers/"+imsi+"/send_sms", text, encoding)
+}
+
+// SendSMSToMSISDN sends an SMS message to a subscriber with the MSISDN and returns the ID of the message.
+// The text is validated before sending; the error matches ErrSMSTooLong if it does not fit in a message.
+func (ac *APIClient) SendSMSToMSISDN(msisdn, text string, encoding SMSEncoding) (string, error) {
+	return ac.SendSMSToMSISDNWithContext(context.Background(), msisdn, text, encoding)
+}
 
-	return subscriber, nil
+// SendSMSToMSISDNWithContext is the context-aware version of SendSMSToMSISDN.
+func (ac *APIClient) SendSMSToMSISDNWithContext(ctx context.Context, msisdn, text string, encoding SMSEncoding) (string, error) {
+	return ac.sendSMS(ctx, "/v1/subscribers/msisdn/"+msisdn+"/send_sms", text, encoding)
+}
+
+func (ac *APIClient) sendSMS(ctx context.Context, path, text string, encoding SMSEncoding) (string, error) {
+	encoding, err := validateSMS(text, encoding)
+	if err != nil {
+		return "", err
+	}
+
+	params := &apiParams{
+		method:      "POST",
+		path:        path,
+		contentType: "application/json",
+		body:        (&sendSMSRequest{EncodingType: encoding, Payload: text}).JSON(),
+	}
+
+	resp, err := ac.callAPI(ctx, params)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	return parseSendSMSResponse(resp)
 }
 
 // PutSubscriberTags puts tags on a subscriber
 func (ac *APIClient) PutSubscriberTags(imsi string, tags []Tag) (*Subscriber, error) {
+	return ac.PutSubscriberTagsWithContext(context.Background(), imsi, tags)
+}
+
+// PutSubscriberTagsWithContext is the context-aware version of PutSubscriberTags.
+func (ac *APIClient) PutSubscriberTagsWithContext(ctx context.Context, imsi string, tags []Tag) (*Subscriber, error) {
 	params := &apiParams{
 		method:      "PUT",
 		path:        "/v1/subscribers/" + imsi + "/tags",
@@ -593,25 +1139,28 @@ func (ac *APIClient) PutSubscriberTags(imsi string, tags []Tag) (*Subscriber, er
 		body:        tagsToJSON(tags),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
-
-	return subscriber, nil
+	return parseSubscriber(resp)
 }
 
 // DeleteSubscriberTag deletes a tag on a subscriber
 func (ac *APIClient) DeleteSubscriberTag(imsi string, tagName string) error {
+	return ac.DeleteSubscriberTagWithContext(context.Background(), imsi, tagName)
+}
+
+// DeleteSubscriberTagWithContext is the context-aware version of DeleteSubscriberTag.
+func (ac *APIClient) DeleteSubscriberTagWithContext(ctx context.Context, imsi string, tagName string) error {
 	params := &apiParams{
 		method: "DELETE",
 		path:   "/v1/subscribers/" + imsi + "/tags/" + percentEncoding(tagName),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return err
 	}
@@ -622,45 +1171,61 @@ func (ac *APIClient) DeleteSubscriberTag(imsi string, tagName string) error {
 
 // GetAirStats gets stats of Air for a subscriber for a specified period
 func (ac *APIClient) GetAirStats(imsi string, from, to time.Time, period StatsPeriod) ([]AirStats, error) {
+	return ac.GetAirStatsWithContext(context.Background(), imsi, from, to, period)
+}
+
+// GetAirStatsWithContext is the context-aware version of GetAirStats.
+func (ac *APIClient) GetAirStatsWithContext(ctx context.Context, imsi string, from, to time.Time, period StatsPeriod) ([]AirStats, error) {
 	params := &apiParams{
 		method: "GET",
 		path:   fmt.Sprintf("/v1/stats/air/subscribers/%s?from=%d&to=%d&period=%s", imsi, from.Unix(), to.Unix(), period.String()),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	airStats := parseAirStats(resp)
-
-	return airStats, nil
+	return parseAirStats(resp, ac.lenientDecoding)
 }
 
 // GetBeamStats gets stats of Beam for a subscriber for a specified period
 func (ac *APIClient) GetBeamStats(imsi string, from, to time.Time, period StatsPeriod) ([]BeamStats, error) {
+	return ac.GetBeamStatsWithContext(context.Background(), imsi, from, to, period)
+}
+
+// GetBeamStatsWithContext is the context-aware version of GetBeamStats.
+func (ac *APIClient) GetBeamStatsWithContext(ctx context.Context, imsi string, from, to time.Time, period StatsPeriod) ([]BeamStats, error) {
 	params := &apiParams{
 		method: "GET",
 		path:   fmt.Sprintf("/v1/stats/beam/subscribers/%s?from=%d&to=%d&period=%s", imsi, from.Unix(), to.Unix(), period.String()),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	beamStats := parseBeamStats(resp)
-
-	return beamStats, nil
+	return parseBeamStats(resp, ac.lenientDecoding)
 }
 
 // ExportAirStats gets a URL to download a CSV file which contains stats of all Air SIMs for the operator for a specified period
 func (ac *APIClient) ExportAirStats(from, to time.Time, period StatsPeriod) (*url.URL, error) {
+	return ac.ExportAirStatsWithContext(context.Background(), from, to, period)
+}
+
+// ExportAirStatsWithContext is the context-aware version of ExportAirStats.
+func (ac *APIClient) ExportAirStatsWithContext(ctx context.Context, from, to time.Time, period StatsPeriod) (*url.URL, error) {
+	operatorID, err := ac.currentOperatorID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	params := &apiParams{
 		method:      "POST",
-		path:        fmt.Sprintf("/v1/stats/air/operators/%s/export", ac.OperatorID),
+		path:        fmt.Sprintf("/v1/stats/air/operators/%s/export", operatorID),
 		contentType: "application/json",
 		body: (&exportAirStatsRequest{
 			From:   from.Unix(),
@@ -669,13 +1234,16 @@ func (ac *APIClient) ExportAirStats(from, to time.Time, period StatsPeriod) (*ur
 		}).JSON(),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	respBody := parseExportAirStatsResponse(resp)
+	respBody, err := parseExportAirStatsResponse(resp)
+	if err != nil {
+		return nil, err
+	}
 	url, err := url.Parse(respBody.URL)
 	if err != nil {
 		return nil, err
@@ -686,9 +1254,19 @@ func (ac *APIClient) ExportAirStats(from, to time.Time, period StatsPeriod) (*ur
 
 // ExportBeamStats gets a URL to download a CSV file which contains all stats of Beam for the operator for a specified period
 func (ac *APIClient) ExportBeamStats(from, to time.Time, period StatsPeriod) (*url.URL, error) {
+	return ac.ExportBeamStatsWithContext(context.Background(), from, to, period)
+}
+
+// ExportBeamStatsWithContext is the context-aware version of ExportBeamStats.
+func (ac *APIClient) ExportBeamStatsWithContext(ctx context.Context, from, to time.Time, period StatsPeriod) (*url.URL, error) {
+	operatorID, err := ac.currentOperatorID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	params := &apiParams{
 		method:      "POST",
-		path:        fmt.Sprintf("/v1/stats/beam/operators/%s/export", ac.OperatorID),
+		path:        fmt.Sprintf("/v1/stats/beam/operators/%s/export", operatorID),
 		contentType: "application/json",
 		body: (&exportBeamStatsRequest{
 			From:   from.Unix(),
@@ -697,13 +1275,16 @@ func (ac *APIClient) ExportBeamStats(from, to time.Time, period StatsPeriod) (*u
 		}).JSON(),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	respBody := parseExportBeamStatsResponse(resp)
+	respBody, err := parseExportBeamStatsResponse(resp)
+	if err != nil {
+		return nil, err
+	}
 	url, err := url.Parse(respBody.URL)
 	if err != nil {
 		return nil, err
@@ -714,6 +1295,11 @@ func (ac *APIClient) ExportBeamStats(from, to time.Time, period StatsPeriod) (*u
 
 // ListGroups lists groups for the operator
 func (ac *APIClient) ListGroups(options *ListGroupsOptions) ([]Group, *PaginationKeys, error) {
+	return ac.ListGroupsWithContext(context.Background(), options)
+}
+
+// ListGroupsWithContext is the context-aware version of ListGroups.
+func (ac *APIClient) ListGroupsWithContext(ctx context.Context, options *ListGroupsOptions) ([]Group, *PaginationKeys, error) {
 	params := &apiParams{
 		method: "GET",
 		path:   "/v1/groups",
@@ -722,22 +1308,22 @@ func (ac *APIClient) ListGroups(options *ListGroupsOptions) ([]Group, *Paginatio
 		params.query = options.String()
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, nil, err
 	}
 	defer resp.Body.Close()
 
-	groups, paginationKeys, err := parseListGroupsResponse(resp)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return groups, paginationKeys, nil
+	return parseListGroupsResponse(resp, ac.lenientDecoding)
 }
 
 // CreateGroup creates a group
 func (ac *APIClient) CreateGroup(tags Tags) (*Group, error) {
+	return ac.CreateGroupWithContext(context.Background(), tags)
+}
+
+// CreateGroupWithContext is the context-aware version of CreateGroup.
+func (ac *APIClient) CreateGroupWithContext(ctx context.Context, tags Tags) (*Group, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/groups",
@@ -747,19 +1333,22 @@ func (ac *APIClient) CreateGroup(tags Tags) (*Group, error) {
 		}).JSON(),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	group := parseGroup(resp)
-
-	return group, nil
+	return parseGroup(resp)
 }
 
 // CreateGroupWithName creates a group with name
 func (ac *APIClient) CreateGroupWithName(name string) (*Group, error) {
+	return ac.CreateGroupWithNameWithContext(context.Background(), name)
+}
+
+// CreateGroupWithNameWithContext is the context-aware version of CreateGroupWithName.
+func (ac *APIClient) CreateGroupWithNameWithContext(ctx context.Context, name string) (*Group, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/groups",
@@ -769,19 +1358,22 @@ func (ac *APIClient) CreateGroupWithName(name string) (*Group, error) {
 		}).JSON(),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	group := parseGroup(resp)
-
-	return group, nil
+	return parseGroup(resp)
 }
 
 // DeleteGroup deletes a group
 func (ac *APIClient) DeleteGroup(groupID string) error {
+	return ac.DeleteGroupWithContext(context.Background(), groupID)
+}
+
+// DeleteGroupWithContext is the context-aware version of DeleteGroup.
+func (ac *APIClient) DeleteGroupWithContext(ctx context.Context, groupID string) error {
 	params := &apiParams{
 		method:      "DELETE",
 		path:        "/v1/groups/" + groupID,
@@ -789,7 +1381,7 @@ func (ac *APIClient) DeleteGroup(groupID string) error {
 		body:        "{}",
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return err
 	}
@@ -800,24 +1392,32 @@ func (ac *APIClient) DeleteGroup(groupID string) error {
 
 // GetGroup gets detailed info about a group
 func (ac *APIClient) GetGroup(groupID string) (*Group, error) {
+	return ac.GetGroupWithContext(context.Background(), groupID)
+}
+
+// GetGroupWithContext is the context-aware version of GetGroup.
+func (ac *APIClient) GetGroupWithContext(ctx context.Context, groupID string) (*Group, error) {
 	params := &apiParams{
 		method: "GET",
 		path:   "/v1/groups/" + groupID,
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	group := parseGroup(resp)
-
-	return group, nil
+	return parseGroup(resp)
 }
 
 // ListSubscribersInGroup lists subscribers in a group
 func (ac *APIClient) ListSubscribersInGroup(groupID string, options *ListSubscribersInGroupOptions) ([]Subscriber, *PaginationKeys, error) {
+	return ac.ListSubscribersInGroupWithContext(context.Background(), groupID, options)
+}
+
+// ListSubscribersInGroupWithContext is the context-aware version of ListSubscribersInGroup.
+func (ac *APIClient) ListSubscribersInGroupWithContext(ctx context.Context, groupID string, options *ListSubscribersInGroupOptions) ([]Subscriber, *PaginationKeys, error) {
 	params := &apiParams{
 		method: "GET",
 		path:   "/v1/groups/" + groupID + "/subscribers",
@@ -826,22 +1426,22 @@ func (ac *APIClient) ListSubscribersInGroup(groupID string, options *ListSubscri
 		params.query = options.String()
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, nil, err
 	}
 	defer resp.Body.Close()
 
-	subscribers, paginationKeys, err := parseListSubscribersResponse(resp)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return subscribers, paginationKeys, nil
+	return parseListSubscribersResponse(resp, ac.lenientDecoding)
 }
 
 // UpdateGroupConfigurations updates configurations for a group
 func (ac *APIClient) UpdateGroupConfigurations(groupID, namespace string, configurations []GroupConfig) (*Group, error) {
+	return ac.UpdateGroupConfigurationsWithContext(context.Background(), groupID, namespace, configurations)
+}
+
+// UpdateGroupConfigurationsWithContext is the context-aware version of UpdateGroupConfigurations.
+func (ac *APIClient) UpdateGroupConfigurationsWithContext(ctx context.Context, groupID, namespace string, configurations []GroupConfig) (*Group, error) {
 	params := &apiParams{
 		method:      "PUT",
 		path:        "/v1/groups/" + groupID + "/configuration/" + namespace,
@@ -849,79 +1449,197 @@ func (ac *APIClient) UpdateGroupConfigurations(groupID, namespace string, config
 		body:        toJSON(configurations),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	group := parseGroup(resp)
-
-	return group, nil
+	return parseGroup(resp)
 }
 
 // UpdateAirConfig updates SORACOM Air configurations for a group
 func (ac *APIClient) UpdateAirConfig(groupID string, airConfig *AirConfig) (*Group, error) {
+	return ac.UpdateAirConfigWithContext(context.Background(), groupID, airConfig)
+}
+
+// UpdateAirConfigWithContext is the context-aware version of UpdateAirConfig.
+func (ac *APIClient) UpdateAirConfigWithContext(ctx context.Context, groupID string, airConfig *AirConfig) (*Group, error) {
 	params := &apiParams{
 		method:      "PUT",
-		path:        "/v1/groups/" + groupID + "/configuration/SoracomAir",
+		path:        "/v1/groups/" + groupID + "/configuration/" + string(ConfigNamespaceAir),
 		contentType: "application/json",
 		body:        airConfig.JSON(),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	group := parseGroup(resp)
+	return parseGroup(resp)
+}
+
+// UpdateFunnelConfig updates SORACOM Funnel configurations for a group.
+// credential is the credential set data is sent with, as returned by CreateCredentialWithName; its ID replaces CredentialsID of funnelConfig.
+// The credential type is checked against the destination by ValidateFunnelCredential before calling the API.
+// credential may be nil if funnelConfig.CredentialsID refers to an existing credential set, in which case its type is not checked.
+func (ac *APIClient) UpdateFunnelConfig(groupID string, funnelConfig *FunnelConfig, credential *CreatedCredential) (*Group, error) {
+	return ac.UpdateFunnelConfigWithContext(context.Background(), groupID, funnelConfig, credential)
+}
+
+// UpdateFunnelConfigWithContext is the context-aware version of UpdateFunnelConfig.
+func (ac *APIClient) UpdateFunnelConfigWithContext(ctx context.Context, groupID string, funnelConfig *FunnelConfig, credential *CreatedCredential) (*Group, error) {
+	if funnelConfig == nil {
+		return nil, ErrFunnelConfigRequired
+	}
+	c := *funnelConfig
+	if credential != nil {
+		c.CredentialsID = credential.CredentialID
+	}
+	if credential != nil || c.CredentialsID == "" {
+		err := ValidateFunnelCredential(c.Destination, credential)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	return group, nil
+	return ac.UpdateGroupConfigurationsWithContext(ctx, groupID, string(ConfigNamespaceFunnel), c.GroupConfigs())
 }
 
 // UpdateBeamTCPConfig updates SORACOM Beam configurations for a group
+//
+// Deprecated: entryPoint must be BeamEntryPointTCP; use UpdateBeamTCPEntryPointConfig instead.
 func (ac *APIClient) UpdateBeamTCPConfig(groupID, entryPoint string, beamTCPConfig *BeamTCPConfig) (*Group, error) {
+	return ac.UpdateBeamTCPConfigWithContext(context.Background(), groupID, entryPoint, beamTCPConfig)
+}
+
+// UpdateBeamTCPConfigWithContext is the context-aware version of UpdateBeamTCPConfig.
+//
+// Deprecated: entryPoint must be BeamEntryPointTCP; use UpdateBeamTCPEntryPointConfigWithContext instead.
+func (ac *APIClient) UpdateBeamTCPConfigWithContext(ctx context.Context, groupID, entryPoint string, beamTCPConfig *BeamTCPConfig) (*Group, error) {
+	if entryPoint != BeamEntryPointTCP {
+		return nil, fmt.Errorf("%w: %q is not the TCP entry point %q", ErrInvalidBeamEntryPoint, entryPoint, BeamEntryPointTCP)
+	}
+	return ac.UpdateBeamTCPEntryPointConfigWithContext(ctx, groupID, beamTCPConfig)
+}
+
+// UpdateBeamTCPEntryPointConfig updates the SORACOM Beam TCP entry point configuration for a group
+func (ac *APIClient) UpdateBeamTCPEntryPointConfig(groupID string, beamTCPConfig *BeamTCPConfig) (*Group, error) {
+	return ac.UpdateBeamTCPEntryPointConfigWithContext(context.Background(), groupID, beamTCPConfig)
+}
+
+// UpdateBeamTCPEntryPointConfigWithContext is the context-aware version of UpdateBeamTCPEntryPointConfig.
+func (ac *APIClient) UpdateBeamTCPEntryPointConfigWithContext(ctx context.Context, groupID string, beamTCPConfig *BeamTCPConfig) (*Group, error) {
+	return ac.updateBeamConfig(ctx, groupID, BeamEntryPointTCP, beamTCPConfig)
+}
+
+// UpdateBeamUDPConfig updates the SORACOM Beam UDP entry point configuration for a group
+func (ac *APIClient) UpdateBeamUDPConfig(groupID string, beamUDPConfig *BeamUDPConfig) (*Group, error) {
+	return ac.UpdateBeamUDPConfigWithContext(context.Background(), groupID, beamUDPConfig)
+}
+
+// UpdateBeamUDPConfigWithContext is the context-aware version of UpdateBeamUDPConfig.
+func (ac *APIClient) UpdateBeamUDPConfigWithContext(ctx context.Context, groupID string, beamUDPConfig *BeamUDPConfig) (*Group, error) {
+	return ac.updateBeamConfig(ctx, groupID, BeamEntryPointUDP, beamUDPConfig)
+}
+
+// UpdateBeamMQTTConfig updates the SORACOM Beam MQTT entry point configuration for a group
+func (ac *APIClient) UpdateBeamMQTTConfig(groupID string, beamMQTTConfig *BeamMQTTConfig) (*Group, error) {
+	return ac.UpdateBeamMQTTConfigWithContext(context.Background(), groupID, beamMQTTConfig)
+}
+
+// UpdateBeamMQTTConfigWithContext is the context-aware version of UpdateBeamMQTTConfig.
+func (ac *APIClient) UpdateBeamMQTTConfigWithContext(ctx context.Context, groupID string, beamMQTTConfig *BeamMQTTConfig) (*Group, error) {
+	return ac.updateBeamConfig(ctx, groupID, BeamEntryPointMQTT, beamMQTTConfig)
+}
+
+// UpdateBeamHTTPConfig updates a SORACOM Beam HTTP entry point configuration for requests to the path of a group, e.g. "/" for all requests
+func (ac *APIClient) UpdateBeamHTTPConfig(groupID, path string, beamHTTPConfig *BeamHTTPConfig) (*Group, error) {
+	return ac.UpdateBeamHTTPConfigWithContext(context.Background(), groupID, path, beamHTTPConfig)
+}
+
+// UpdateBeamHTTPConfigWithContext is the context-aware version of UpdateBeamHTTPConfig.
+func (ac *APIClient) UpdateBeamHTTPConfigWithContext(ctx context.Context, groupID, path string, beamHTTPConfig *BeamHTTPConfig) (*Group, error) {
+	entryPoint, err := beamPathEntryPoint(BeamHTTPEntryPoint, path)
+	if err != nil {
+		return nil, err
+	}
+	return ac.updateBeamConfig(ctx, groupID, entryPoint, beamHTTPConfig)
+}
+
+// UpdateBeamWebsiteConfig updates the SORACOM Beam website entry point configuration for a group
+func (ac *APIClient) UpdateBeamWebsiteConfig(groupID string, beamWebsiteConfig *BeamWebsiteConfig) (*Group, error) {
+	return ac.UpdateBeamWebsiteConfigWithContext(context.Background(), groupID, beamWebsiteConfig)
+}
+
+// UpdateBeamWebsiteConfigWithContext is the context-aware version of UpdateBeamWebsiteConfig.
+func (ac *APIClient) UpdateBeamWebsiteConfigWithContext(ctx context.Context, groupID string, beamWebsiteConfig *BeamWebsiteConfig) (*Group, error) {
+	return ac.updateBeamConfig(ctx, groupID, BeamEntryPointWebsite, beamWebsiteConfig)
+}
+
+// UpdateBeamWebhookConfig updates a SORACOM Beam webhook entry point configuration for requests to the path of a group, e.g. "/" for all requests
+func (ac *APIClient) UpdateBeamWebhookConfig(groupID, path string, beamWebhookConfig *BeamWebhookConfig) (*Group, error) {
+	return ac.UpdateBeamWebhookConfigWithContext(context.Background(), groupID, path, beamWebhookConfig)
+}
+
+// UpdateBeamWebhookConfigWithContext is the context-aware version of UpdateBeamWebhookConfig.
+func (ac *APIClient) UpdateBeamWebhookConfigWithContext(ctx context.Context, groupID, path string, beamWebhookConfig *BeamWebhookConfig) (*Group, error) {
+	entryPoint, err := beamPathEntryPoint(BeamWebhookEntryPoint, path)
+	if err != nil {
+		return nil, err
+	}
+	return ac.updateBeamConfig(ctx, groupID, entryPoint, beamWebhookConfig)
+}
+
+func (ac *APIClient) updateBeamConfig(ctx context.Context, groupID, entryPoint string, config interface{}) (*Group, error) {
 	params := &apiParams{
 		method:      "PUT",
-		path:        "/v1/groups/" + groupID + "/configuration/SoracomBeam",
+		path:        "/v1/groups/" + groupID + "/configuration/" + string(ConfigNamespaceBeam),
 		contentType: "application/json",
 		body: toJSON([]GroupConfig{
-			{Key: entryPoint, Value: beamTCPConfig},
+			{Key: entryPoint, Value: config},
 		}),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	group := parseGroup(resp)
-
-	return group, nil
+	return parseGroup(resp)
 }
 
 // DeleteGroupConfiguration deletes a configuration for a group
 func (ac *APIClient) DeleteGroupConfiguration(groupID, namespace, name string) (*Group, error) {
+	return ac.DeleteGroupConfigurationWithContext(context.Background(), groupID, namespace, name)
+}
+
+// DeleteGroupConfigurationWithContext is the context-aware version of DeleteGroupConfiguration.
+func (ac *APIClient) DeleteGroupConfigurationWithContext(ctx context.Context, groupID, namespace, name string) (*Group, error) {
 	params := &apiParams{
 		method: "DELETE",
 		path:   "/v1/groups/" + groupID + "/configuration/" + namespace + "/" + percentEncoding(name),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	group := parseGroup(resp)
-
-	return group, nil
+	return parseGroup(resp)
 }
 
 // UpdateGroupTags updates tags a group
 func (ac *APIClient) UpdateGroupTags(groupID string, tags []Tag) (*Group, error) {
+	return ac.UpdateGroupTagsWithContext(context.Background(), groupID, tags)
+}
+
+// UpdateGroupTagsWithContext is the context-aware version of UpdateGroupTags.
+func (ac *APIClient) UpdateGroupTagsWithContext(ctx context.Context, groupID string, tags []Tag) (*Group, error) {
 	params := &apiParams{
 		method:      "PUT",
 		path:        "/v1/groups/" + groupID + "/tags",
@@ -929,25 +1647,28 @@ func (ac *APIClient) UpdateGroupTags(groupID string, tags []Tag) (*Group, error)
 		body:        toJSON(tags),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	group := parseGroup(resp)
-
-	return group, nil
+	return parseGroup(resp)
 }
 
 // DeleteGroupTag deletes a tag for a group
 func (ac *APIClient) DeleteGroupTag(groupID, tagName string) error {
+	return ac.DeleteGroupTagWithContext(context.Background(), groupID, tagName)
+}
+
+// DeleteGroupTagWithContext is the context-aware version of DeleteGroupTag.
+func (ac *APIClient) DeleteGroupTagWithContext(ctx context.Context, groupID, tagName string) error {
 	params := &apiParams{
 		method: "DELETE",
 		path:   "/v1/groups/" + groupID + "/tags/" + percentEncoding(tagName),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return err
 	}
@@ -958,6 +1679,11 @@ func (ac *APIClient) DeleteGroupTag(groupID, tagName string) error {
 
 // ListEventHandlers lists event handlers for the operator
 func (ac *APIClient) ListEventHandlers(options *ListEventHandlersOptions) ([]EventHandler, error) {
+	return ac.ListEventHandlersWithContext(context.Background(), options)
+}
+
+// ListEventHandlersWithContext is the context-aware version of ListEventHandlers.
+func (ac *APIClient) ListEventHandlersWithContext(ctx context.Context, options *ListEventHandlersOptions) ([]EventHandler, error) {
 	params := &apiParams{
 		method: "GET",
 		path:   "/v1/event_handlers",
@@ -966,22 +1692,22 @@ func (ac *APIClient) ListEventHandlers(options *ListEventHandlersOptions) ([]Eve
 		params.query = options.String()
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	eventHandlers, err := parseListEventHandlersResponse(resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return eventHandlers, nil
+	return parseListEventHandlersResponse(resp, ac.lenientDecoding)
 }
 
 // CreateEventHandler creates an event handler
 func (ac *APIClient) CreateEventHandler(options *CreateEventHandlerOptions) (*EventHandler, error) {
+	return ac.CreateEventHandlerWithContext(context.Background(), options)
+}
+
+// CreateEventHandlerWithContext is the context-aware version of CreateEventHandler.
+func (ac *APIClient) CreateEventHandlerWithContext(ctx context.Context, options *CreateEventHandlerOptions) (*EventHandler, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/event_handlers",
@@ -989,7 +1715,7 @@ func (ac *APIClient) CreateEventHandler(options *CreateEventHandlerOptions) (*Ev
 		body:        options.JSON(),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
@@ -1004,34 +1730,39 @@ func (ac *APIClient) CreateEventHandler(options *CreateEventHandlerOptions) (*Ev
 
 // ListEventHandlersForSubscriber creates an event handler with the specified options
 func (ac *APIClient) ListEventHandlersForSubscriber(imsi string) ([]EventHandler, error) {
+	return ac.ListEventHandlersForSubscriberWithContext(context.Background(), imsi)
+}
+
+// ListEventHandlersForSubscriberWithContext is the context-aware version of ListEventHandlersForSubscriber.
+func (ac *APIClient) ListEventHandlersForSubscriberWithContext(ctx context.Context, imsi string) ([]EventHandler, error) {
 	params := &apiParams{
 		method:      "GET",
 		path:        "/v1/event_handlers/subscribers/" + imsi,
 		contentType: "application/json",
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	eventHandlers, err := parseListEventHandlersResponse(resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return eventHandlers, nil
+	return parseListEventHandlersResponse(resp, ac.lenientDecoding)
 }
 
 // DeleteEventHandler deletes the specified event handler
 func (ac *APIClient) DeleteEventHandler(handlerID string) error {
+	return ac.DeleteEventHandlerWithContext(context.Background(), handlerID)
+}
+
+// DeleteEventHandlerWithContext is the context-aware version of DeleteEventHandler.
+func (ac *APIClient) DeleteEventHandlerWithContext(ctx context.Context, handlerID string) error {
 	params := &apiParams{
 		method: "DELETE",
 		path:   "/v1/event_handlers/" + handlerID,
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return err
 	}
@@ -1042,12 +1773,17 @@ func (ac *APIClient) DeleteEventHandler(handlerID string) error {
 
 // GetEventHandler gets the specified event handler
 func (ac *APIClient) GetEventHandler(handlerID string) (*EventHandler, error) {
+	return ac.GetEventHandlerWithContext(context.Background(), handlerID)
+}
+
+// GetEventHandlerWithContext is the context-aware version of GetEventHandler.
+func (ac *APIClient) GetEventHandlerWithContext(ctx context.Context, handlerID string) (*EventHandler, error) {
 	params := &apiParams{
 		method: "GET",
 		path:   "/v1/event_handlers/" + handlerID,
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
@@ -1062,6 +1798,11 @@ func (ac *APIClient) GetEventHandler(handlerID string) (*EventHandler, error) {
 
 // UpdateEventHandler updates the specified event handler
 func (ac *APIClient) UpdateEventHandler(eh *EventHandler) error {
+	return ac.UpdateEventHandlerWithContext(context.Background(), eh)
+}
+
+// UpdateEventHandlerWithContext is the context-aware version of UpdateEventHandler.
+func (ac *APIClient) UpdateEventHandlerWithContext(ctx context.Context, eh *EventHandler) error {
 	params := &apiParams{
 		method:      "PUT",
 		path:        "/v1/event_handlers/" + eh.HandlerID,
@@ -1069,7 +1810,7 @@ func (ac *APIClient) UpdateEventHandler(eh *EventHandler) error {
 		body:        eh.JSON(),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return err
 	}
@@ -1080,6 +1821,11 @@ func (ac *APIClient) UpdateEventHandler(eh *EventHandler) error {
 
 // RegisterPaymentMethodWebPay registers the specified WebPay information as an active payment method
 func (ac *APIClient) RegisterPaymentMethodWebPay(wp *PaymentMethodInfoWebPay) error {
+	return ac.RegisterPaymentMethodWebPayWithContext(context.Background(), wp)
+}
+
+// RegisterPaymentMethodWebPayWithContext is the context-aware version of RegisterPaymentMethodWebPay.
+func (ac *APIClient) RegisterPaymentMethodWebPayWithContext(ctx context.Context, wp *PaymentMethodInfoWebPay) error {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/payment_methods/webpay",
@@ -1087,7 +1833,7 @@ func (ac *APIClient) RegisterPaymentMethodWebPay(wp *PaymentMethodInfoWebPay) er
 		body:        wp.JSON(),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return err
 	}
@@ -1098,6 +1844,11 @@ func (ac *APIClient) RegisterPaymentMethodWebPay(wp *PaymentMethodInfoWebPay) er
 
 // RegisterPaymentMethodPayJP registers the specified payment method tokens as an active payment method
 func (ac *APIClient) RegisterPaymentMethodPayJP(pm *PaymentMethodInfoPayJP) error {
+	return ac.RegisterPaymentMethodPayJPWithContext(context.Background(), pm)
+}
+
+// RegisterPaymentMethodPayJPWithContext is the context-aware version of RegisterPaymentMethodPayJP.
+func (ac *APIClient) RegisterPaymentMethodPayJPWithContext(ctx context.Context, pm *PaymentMethodInfoPayJP) error {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/payment_methods/token/payjp",
@@ -1105,7 +1856,7 @@ func (ac *APIClient) RegisterPaymentMethodPayJP(pm *PaymentMethodInfoPayJP) erro
 		body:        pm.JSON(),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return err
 	}
@@ -1116,6 +1867,11 @@ func (ac *APIClient) RegisterPaymentMethodPayJP(pm *PaymentMethodInfoPayJP) erro
 
 // GetSignupToken retrieves token to complete signup (sandbox environment only)
 func (ac *APIClient) GetSignupToken(email, authKeyID, authKey string) (string, error) {
+	return ac.GetSignupTokenWithContext(context.Background(), email, authKeyID, authKey)
+}
+
+// GetSignupTokenWithContext is the context-aware version of GetSignupToken.
+func (ac *APIClient) GetSignupTokenWithContext(ctx context.Context, email, authKeyID, authKey string) (string, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/sandbox/operators/token/" + email,
@@ -1126,7 +1882,7 @@ func (ac *APIClient) GetSignupToken(email, authKeyID, authKey string) (string, e
 		}).JSON(),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return "", err
 	}
@@ -1143,6 +1899,11 @@ func (ac *APIClient) GetSignupToken(email, authKeyID, authKey string) (string, e
 // InitOperatorForSandbox initializes an operator for sandbox environment.
 // see also: https://developers.soracom.io/en/docs/tools/api-sandbox/
 func (ac *APIClient) InitOperatorForSandbox(email, password, authKeyID, authKey string, registerPaymentMethod bool, coverageTypes []string) (*InitOperatorForSandboxResponse, error) {
+	return ac.InitOperatorForSandboxWithContext(context.Background(), email, password, authKeyID, authKey, registerPaymentMethod, coverageTypes)
+}
+
+// InitOperatorForSandboxWithContext is the context-aware version of InitOperatorForSandbox.
+func (ac *APIClient) InitOperatorForSandboxWithContext(ctx context.Context, email, password, authKeyID, authKey string, registerPaymentMethod bool, coverageTypes []string) (*InitOperatorForSandboxResponse, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/sandbox/init",
@@ -1157,7 +1918,7 @@ func (ac *APIClient) InitOperatorForSandbox(email, password, authKeyID, authKey
 		}).JSON(),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
@@ -1183,6 +1944,11 @@ func (ac *APIClient) InitOperatorForSandbox(email, password, authKeyID, authKey
 
 // CreateSubscriber sends a request to create a brand-new subscriber
 func (ac *APIClient) CreateSubscriber() (*CreatedSubscriber, error) {
+	return ac.CreateSubscriberWithContext(context.Background())
+}
+
+// CreateSubscriberWithContext is the context-aware version of CreateSubscriber.
+func (ac *APIClient) CreateSubscriberWithContext(ctx context.Context) (*CreatedSubscriber, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/sandbox/subscribers/create",
@@ -1190,7 +1956,7 @@ func (ac *APIClient) CreateSubscriber() (*CreatedSubscriber, error) {
 		body:        "",
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
@@ -1206,6 +1972,11 @@ func (ac *APIClient) CreateSubscriber() (*CreatedSubscriber, error) {
 
 // InsertAirStats inserts a set of data communication stats with a timestamp for a subscriber
 func (ac *APIClient) InsertAirStats(imsi string, stats AirStats) error {
+	return ac.InsertAirStatsWithContext(context.Background(), imsi, stats)
+}
+
+// InsertAirStatsWithContext is the context-aware version of InsertAirStats.
+func (ac *APIClient) InsertAirStatsWithContext(ctx context.Context, imsi string, stats AirStats) error {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/sandbox/stats/air/subscribers/" + imsi,
@@ -1213,7 +1984,7 @@ func (ac *APIClient) InsertAirStats(imsi string, stats AirStats) error {
 		body:        stats.JSON(),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return err
 	}
@@ -1224,6 +1995,11 @@ func (ac *APIClient) InsertAirStats(imsi string, stats AirStats) error {
 
 // InsertBeamStats inserts a set of beam stats with a timestamp for a subscriber
 func (ac *APIClient) InsertBeamStats(imsi string, stats BeamStats) error {
+	return ac.InsertBeamStatsWithContext(context.Background(), imsi, stats)
+}
+
+// InsertBeamStatsWithContext is the context-aware version of InsertBeamStats.
+func (ac *APIClient) InsertBeamStatsWithContext(ctx context.Context, imsi string, stats BeamStats) error {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/sandbox/stats/beam/subscribers/" + imsi,
@@ -1231,7 +2007,7 @@ func (ac *APIClient) InsertBeamStats(imsi string, stats BeamStats) error {
 		body:        stats.JSON(),
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return err
 	}
@@ -1242,12 +2018,26 @@ func (ac *APIClient) InsertBeamStats(imsi string, stats BeamStats) error {
 
 // DeleteSandboxOperator deletes a sandbox operator
 func (ac *APIClient) DeleteSandboxOperator() error {
+	return ac.DeleteSandboxOperatorWithContext(context.Background())
+}
+
+// DeleteSandboxOperatorWithContext is the context-aware version of DeleteSandboxOperator.
+func (ac *APIClient) DeleteSandboxOperatorWithContext(ctx context.Context) error {
+	// checked before authenticating so that credentials are never sent to look up the operator of a non-sandbox endpoint
+	if !ac.sandbox {
+		return ErrNotSandbox
+	}
+	operatorID, err := ac.currentOperatorID(ctx)
+	if err != nil {
+		return err
+	}
+
 	params := &apiParams{
 		method: "DELETE",
-		path:   "/v1/sandbox/operators/" + ac.OperatorID,
+		path:   "/v1/sandbox/operators/" + operatorID,
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return err
 	}
@@ -1258,6 +2048,11 @@ func (ac *APIClient) DeleteSandboxOperator() error {
 
 // CreateCoupon sends a request to create a brand-new coupon
 func (ac *APIClient) CreateCoupon(options *CreatedCouponOptions) (*CreatedCoupon, error) {
+	return ac.CreateCouponWithContext(context.Background(), options)
+}
+
+// CreateCouponWithContext is the context-aware version of CreateCoupon.
+func (ac *APIClient) CreateCouponWithContext(ctx context.Context, options *CreatedCouponOptions) (*CreatedCoupon, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/sandbox/coupons/create",
@@ -1268,7 +2063,7 @@ func (ac *APIClient) CreateCoupon(options *CreatedCouponOptions) (*CreatedCoupon
 		params.body = options.JSON()
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
@@ -1284,6 +2079,11 @@ func (ac *APIClient) CreateCoupon(options *CreatedCouponOptions) (*CreatedCoupon
 
 // CreateCredentialWithName sends a request to create a brand-new credential
 func (ac *APIClient) CreateCredentialWithName(name string, options *CredentialOptions) (*CreatedCredential, error) {
+	return ac.CreateCredentialWithNameWithContext(context.Background(), name, options)
+}
+
+// CreateCredentialWithNameWithContext is the context-aware version of CreateCredentialWithName.
+func (ac *APIClient) CreateCredentialWithNameWithContext(ctx context.Context, name string, options *CredentialOptions) (*CreatedCredential, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/credentials/" + name,
@@ -1294,7 +2094,7 @@ func (ac *APIClient) CreateCredentialWithName(name string, options *CredentialOp
 		params.body = options.JSON()
 	}
 
-	resp, err := ac.callAPI(params)
+	resp, err := ac.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
@@ -95,6 +95,7 @@ func setupAPIClient() *APIClient {
 
 	options := &APIClientOptions{
 		Endpoint: endpoint,
+		Sandbox:  true,
 	}
 
 	return NewAPIClient(options)
@@ -337,11 +338,11 @@ func TestGetSupportToken(t *testing.T) {
 }
 
 func TestGetOperator(t *testing.T) {
-	o, err := apiClient.GetOperator(apiClient.OperatorID)
+	o, err := apiClient.GetOperator(apiClient.OperatorID())
 	if err != nil {
 		t.Fatalf("GetOperator() failed")
 	}
-	if o.OperatorID != apiClient.OperatorID {
+	if o.OperatorID != apiClient.OperatorID() {
 		t.Fatalf("Got an unexpected operator")
 	}
 }
@@ -1370,7 +1371,6 @@ func TestUpdateBeamTCPConfig(t *testing.T) {
 		_ = apiClient.DeleteGroup(group.GroupID)
 	}()
 
-	entryPoint1 := "tcp://beam.soracom.io:8023"
 	beamTCPConfig1 := &BeamTCPConfig{
 		Name:                "TCP Config Name 1",
 		Destination:         "tcps://tcp.example.com:1234",
@@ -1380,9 +1380,9 @@ func TestUpdateBeamTCPConfig(t *testing.T) {
 		PSK:                 "Pre-Shared Key",
 	}
 
-	g1, err := apiClient.UpdateBeamTCPConfig(group.GroupID, entryPoint1, beamTCPConfig1)
+	g1, err := apiClient.UpdateBeamTCPEntryPointConfig(group.GroupID, beamTCPConfig1)
 	if err != nil {
-		t.Fatalf("UpdateBeamTCPConfig() failed: %v", err.Error())
+		t.Fatalf("UpdateBeamTCPEntryPointConfig() failed: %v", err.Error())
 	}
 	beam1 := g1.Configuration["SoracomBeam"].(map[string]interface{})
 	cfg1 := beam1["tcp://beam.soracom.io:8023"].(map[string]interface{})
@@ -1412,9 +1412,9 @@ func TestDeleteGroupConfiguration(t *testing.T) {
 		PSK:                 "Pre-Shared Key",
 	}
 
-	g1, err := apiClient.UpdateBeamTCPConfig(group.GroupID, entryPoint1, beamTCPConfig1)
+	g1, err := apiClient.UpdateBeamTCPEntryPointConfig(group.GroupID, beamTCPConfig1)
 	if err != nil {
-		t.Fatalf("UpdateBeamTCPConfig() failed: %v", err.Error())
+		t.Fatalf("UpdateBeamTCPEntryPointConfig() failed: %v", err.Error())
 	}
 	beam1 := g1.Configuration["SoracomBeam"].(map[string]interface{})
 	cfg1 := beam1[entryPoint1].(map[string]interface{})
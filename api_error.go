@@ -2,22 +2,96 @@ package soracom
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 )
 
+// Sentinel errors matched by APIError with errors.Is
+var (
+	// ErrAuthFailed matches errors caused by invalid or expired credentials
+	ErrAuthFailed = errors.New("authentication failed")
+
+	// ErrNotFound matches errors caused by a resource which does not exist
+	ErrNotFound = errors.New("resource not found")
+
+	// ErrRateLimited matches errors caused by exceeding the rate limit of the API
+	ErrRateLimited = errors.New("rate limit exceeded")
+
+	// ErrInvalidStateTransition matches errors caused by changing a resource to a state it cannot move to from the current state
+	ErrInvalidStateTransition = errors.New("invalid state transition")
+)
+
+// requestIDHeaders are headers which may carry the ID of a request, in the order of preference
+var requestIDHeaders = []string{"X-Soracom-Request-Id", "X-Amzn-Requestid", "X-Request-Id"}
+
+// errorCodes maps SORACOM error codes to sentinel errors for errors which cannot be told from the HTTP status code alone,
+// e.g. invalid state transitions which are returned with 400 Bad Request. Codes are those of the error responses listed in
+// the SORACOM API reference for the operations noted below.
+//
+// Auth Manager (AUM) codes are deliberately not listed: they may also come with 400 Bad Request, e.g. for a wrong
+// current password given to Operator:updateOperatorPassword, which must not make the client authenticate again and
+// replay the request. Auth failures are matched by 401 Unauthorized only.
+var errorCodes = map[string]error{
+	// Subscriber:getSubscriber and other /v1/subscribers/{imsi} operations: no subscriber with the IMSI
+	"SEM0095": ErrNotFound,
+
+	// Subscriber:activateSubscriber, deactivateSubscriber, suspendSubscriber, setSubscriberToStandby and
+	// terminateSubscriber: the status cannot be changed from the current status
+	"SEM0008": ErrInvalidStateTransition,
+
+	// Subscriber:terminateSubscriber: termination is protected or the subscriber is not in a status which can be terminated
+	"SEM0049": ErrInvalidStateTransition,
+}
+
 // APIError represents an error ocurred while calling API
 type APIError struct {
 	HTTPStatusCode int
 	ErrorCode      string
 	Message        string
+
+	// MessageArgs holds the arguments embedded in Message
+	MessageArgs []interface{}
+
+	// RequestID identifies the request on the server side. It is empty if the server did not return one.
+	RequestID string
+
+	// Header holds the headers of the error response
+	Header http.Header
+}
+
+// messageArgs accepts either a list or a single string, which older API versions returned
+type messageArgs []interface{}
+
+func (a *messageArgs) UnmarshalJSON(b []byte) error {
+	var list []interface{}
+	if err := json.Unmarshal(b, &list); err == nil {
+		for i, v := range list {
+			// integers are decoded as float64, which cannot be formatted with %d
+			if f, ok := v.(float64); ok && f == float64(int64(f)) {
+				list[i] = int64(f)
+			}
+		}
+		*a = list
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if v == nil || v == "" {
+		*a = nil
+		return nil
+	}
+	*a = messageArgs{v}
+	return nil
 }
 
 type apiErrorResponse struct {
-	ErrorCode   string `json:"code"`
-	Message     string `json:"message"`
-	MessageArgs string `json:"messageArgs"`
+	ErrorCode   string      `json:"code"`
+	Message     string      `json:"message"`
+	MessageArgs messageArgs `json:"messageArgs"`
 }
 
 func parseAPIErrorResponse(resp *http.Response) *apiErrorResponse {
@@ -27,9 +101,18 @@ func parseAPIErrorResponse(resp *http.Response) *apiErrorResponse {
 	return &aer
 }
 
+// formatMessage embeds args into a message. Messages without format verbs are returned as they are.
+func formatMessage(message string, args []interface{}) string {
+	if len(args) == 0 || !strings.Contains(message, "%") {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
 // NewAPIError creates an instance of APIError from http.Response
 func NewAPIError(resp *http.Response) *APIError {
 	var errorCode, message string
+	var args []interface{}
 	ct := resp.Header.Get("Content-Type")
 
 	if strings.Index(ct, "text/plain") == 0 {
@@ -40,7 +123,8 @@ func NewAPIError(resp *http.Response) *APIError {
 			resp.StatusCode < http.StatusInternalServerError {
 			aer := parseAPIErrorResponse(resp)
 			errorCode = aer.ErrorCode
-			message = fmt.Sprintf(aer.Message, aer.MessageArgs)
+			args = aer.MessageArgs
+			message = formatMessage(aer.Message, args)
 		} else {
 			errorCode = ""
 			message = readAll(resp.Body)
@@ -49,13 +133,58 @@ func NewAPIError(resp *http.Response) *APIError {
 		errorCode = "INT0001"
 		message = "Content-Type: " + ct + " is not supported"
 	}
+
+	var requestID string
+	for _, h := range requestIDHeaders {
+		if requestID = resp.Header.Get(h); requestID != "" {
+			break
+		}
+	}
+
 	return &APIError{
 		HTTPStatusCode: resp.StatusCode,
 		ErrorCode:      errorCode,
 		Message:        message,
+		MessageArgs:    args,
+		RequestID:      requestID,
+		Header:         resp.Header.Clone(),
 	}
 }
 
 func (ae *APIError) Error() string {
+	if ae.Message == "" {
+		return fmt.Sprintf("%d %s %s", ae.HTTPStatusCode, http.StatusText(ae.HTTPStatusCode), ae.ErrorCode)
+	}
 	return ae.Message
 }
+
+// Is reports whether the error matches target, which is one of the sentinel errors such as ErrNotFound
+func (ae *APIError) Is(target error) bool {
+	if ae.ErrorCode != "" && errorCodes[ae.ErrorCode] == target {
+		return true
+	}
+	switch target {
+	case ErrAuthFailed:
+		return ae.HTTPStatusCode == http.StatusUnauthorized
+	case ErrNotFound:
+		return ae.HTTPStatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return ae.HTTPStatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// IsRetryable reports whether the API call may succeed if it is retried
+func (ae *APIError) IsRetryable() bool {
+	return isRetryableStatus(ae.HTTPStatusCode)
+}
+
+// IsRetryable reports whether err is a transient error, i.e. an APIError with status 429 or 5xx, or a transient network error.
+// The API call which returned err may succeed if it is retried.
+func IsRetryable(err error) bool {
+	var ae *APIError
+	if errors.As(err, &ae) {
+		return ae.IsRetryable()
+	}
+	return isTransientNetworkError(err)
+}
@@ -2,6 +2,8 @@ package soracom
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
@@ -87,3 +89,91 @@ func TestNotSupportedContentTypeApiError(t *testing.T) {
 		t.Fatalf("wrong error code: %v", apiError.ErrorCode)
 	}
 }
+
+func TestJsonClientApiErrorMessageArgs(t *testing.T) {
+	testData := []struct {
+		body     string
+		expected string
+	}{
+		{`{"code":"SEM0001","message":"Subscriber %s is in %s status","messageArgs":["001010000000001","active"]}`, "Subscriber 001010000000001 is in active status"},
+		{`{"code":"SEM0001","message":"Limit is %d","messageArgs":[100]}`, "Limit is 100"},
+		{`{"code":"SEM0001","message":"Group %s not found","messageArgs":"group-1"}`, "Group group-1 not found"},
+		{`{"code":"SEM0001","message":"100% done","messageArgs":[]}`, "100% done"},
+		{`{"code":"SEM0001","message":"no args"}`, "no args"},
+	}
+	for _, data := range testData {
+		h := http.Header{}
+		h.Set("Content-Type", "application/json")
+		res := &http.Response{
+			StatusCode: 400,
+			Body:       io.NopCloser(strings.NewReader(data.body)),
+			Header:     h,
+		}
+		apiError := NewAPIError(res)
+		if apiError.Error() != data.expected {
+			t.Errorf("expected %q, got %q", data.expected, apiError.Error())
+		}
+	}
+}
+
+func TestApiErrorIs(t *testing.T) {
+	newError := func(status int, code string) error {
+		h := http.Header{}
+		h.Set("Content-Type", "application/json")
+		h.Set("X-Soracom-Request-Id", "request-1")
+		return NewAPIError(&http.Response{
+			StatusCode: status,
+			Body:       io.NopCloser(strings.NewReader(`{"code":"` + code + `","message":"error"}`)),
+			Header:     h,
+		})
+	}
+
+	testData := []struct {
+		err       error
+		target    error
+		retryable bool
+	}{
+		{newError(401, "AUM0001"), ErrAuthFailed, false},
+		{newError(404, "SEM0095"), ErrNotFound, false},
+		{newError(429, "COM0001"), ErrRateLimited, true},
+	}
+	for _, data := range testData {
+		wrapped := fmt.Errorf("wrapped: %w", data.err)
+		if !errors.Is(wrapped, data.target) {
+			t.Errorf("%v should match %v", data.err, data.target)
+		}
+		if errors.Is(wrapped, ErrInvalidStateTransition) {
+			t.Errorf("%v should not match ErrInvalidStateTransition", data.err)
+		}
+		if IsRetryable(wrapped) != data.retryable {
+			t.Errorf("IsRetryable(%v): expected %v", data.err, data.retryable)
+		}
+	}
+
+	for _, code := range []string{"SEM0008", "SEM0049"} {
+		err := newError(400, code)
+		if !errors.Is(err, ErrInvalidStateTransition) || errors.Is(err, ErrNotFound) || errors.Is(err, ErrAuthFailed) {
+			t.Errorf("%s should match ErrInvalidStateTransition only: %v", code, err)
+		}
+	}
+	if err := newError(400, "AUM0002"); errors.Is(err, ErrAuthFailed) {
+		t.Errorf("auth error code should not match ErrAuthFailed without 401: %v", err)
+	}
+	if err := newError(400, "SEM0095"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("error code should match ErrNotFound regardless of the status: %v", err)
+	}
+	if err := newError(400, "COM0001"); errors.Is(err, ErrInvalidStateTransition) || errors.Is(err, ErrNotFound) || errors.Is(err, ErrAuthFailed) {
+		t.Errorf("unknown error code should not match any sentinel: %v", err)
+	}
+
+	err := newError(400, "SEM0008")
+
+	var apiError *APIError
+	if !errors.As(err, &apiError) || apiError.RequestID != "request-1" || apiError.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("request ID and headers were not captured: %+v", apiError)
+	}
+
+	if IsRetryable(errors.New("unknown")) || !IsRetryable(newError(503, "")) {
+		t.Error("unexpected IsRetryable() result")
+	}
+}
@@ -0,0 +1,154 @@
+package soracom
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// fakeSAMServer accepts SAM user logins and switch_user requests, and tracks the latest token.
+type fakeSAMServer struct {
+	mu            sync.Mutex
+	authBodies    []AuthRequest
+	switchBodies  []switchUserRequest
+	currentToken  string
+	currentUser   string
+	tokenSequence int
+}
+
+func (s *fakeSAMServer) respond(w http.ResponseWriter, operatorID, userName string) {
+	s.tokenSequence++
+	s.currentToken = fmt.Sprintf("token-%d", s.tokenSequence)
+	s.currentUser = operatorID + "/" + userName
+	_, _ = w.Write([]byte(toJSON(&AuthResponse{APIKey: "api-key", Token: s.currentToken, OperatorID: operatorID, UserName: userName})))
+}
+
+func (s *fakeSAMServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Path {
+	case "/v1/auth":
+		var body AuthRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		s.authBodies = append(s.authBodies, body)
+		s.respond(w, body.OperatorID, body.UserName)
+	case "/v1/auth/switch_user":
+		if r.Header.Get("X-Soracom-Token") != s.currentToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"code":"AUM0001","message":"invalid token"}`))
+			return
+		}
+		var body switchUserRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		s.switchBodies = append(s.switchBodies, body)
+		s.respond(w, body.OperatorID, body.UserName)
+	default:
+		if r.Header.Get("X-Soracom-Token") != s.currentToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"code":"AUM0001","message":"invalid token"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"operatorId":"` + s.currentUser + `"}`))
+	}
+}
+
+func (s *fakeSAMServer) expireToken() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.currentToken = "expired"
+}
+
+func TestAuthWithSAMUser(t *testing.T) {
+	s := &fakeSAMServer{}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	ac := NewAPIClient(&APIClientOptions{Endpoint: ts.URL})
+	err := ac.AuthWithSAMUser("OP0000000001", "sam-user", "password")
+	if err != nil {
+		t.Fatalf("AuthWithSAMUser() failed: %v", err)
+	}
+	b := s.authBodies[0]
+	if b.OperatorID != "OP0000000001" || b.UserName != "sam-user" || b.Password != "password" || b.Email != "" {
+		t.Fatalf("unexpected auth request: %+v", b)
+	}
+	if ac.OperatorID() != "OP0000000001" || ac.UserName() != "sam-user" {
+		t.Fatalf("unexpected operator/user: %s, %s", ac.OperatorID(), ac.UserName())
+	}
+
+	s.expireToken()
+	_, err = ac.GetOperator(ac.OperatorID())
+	if err != nil {
+		t.Fatalf("GetOperator() failed: %v", err)
+	}
+	if len(s.authBodies) != 2 || s.authBodies[1].UserName != "sam-user" {
+		t.Fatalf("SAM user did not authenticate again: %+v", s.authBodies)
+	}
+}
+
+func TestAuthWithMFA(t *testing.T) {
+	s := &fakeSAMServer{}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	ac := NewAPIClient(&APIClientOptions{Endpoint: ts.URL})
+	err := ac.AuthWithSAMUserAndMFA("OP0000000001", "sam-user", "password", "123456")
+	if err != nil {
+		t.Fatalf("AuthWithSAMUserAndMFA() failed: %v", err)
+	}
+	if s.authBodies[0].MFAOTPCode != "123456" {
+		t.Fatalf("one-time code was not sent: %+v", s.authBodies[0])
+	}
+
+	s.expireToken()
+	_, err = ac.GetOperator(ac.OperatorID())
+	if !isAuthError(err) {
+		t.Fatalf("expected an auth error, got %v", err)
+	}
+	if len(s.authBodies) != 1 {
+		t.Fatalf("one-time code should not be replayed: %+v", s.authBodies)
+	}
+}
+
+func TestSwitchUser(t *testing.T) {
+	s := &fakeSAMServer{}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	ac := NewAPIClient(&APIClientOptions{Endpoint: ts.URL})
+	err := ac.AuthWithSAMUser("OP0000000001", "sam-user", "password")
+	if err != nil {
+		t.Fatalf("AuthWithSAMUser() failed: %v", err)
+	}
+
+	err = ac.SwitchUser("OP0000000002", "switched-user")
+	if err != nil {
+		t.Fatalf("SwitchUser() failed: %v", err)
+	}
+	if ac.OperatorID() != "OP0000000002" || ac.UserName() != "switched-user" {
+		t.Fatalf("unexpected operator/user: %s, %s", ac.OperatorID(), ac.UserName())
+	}
+
+	o, err := ac.GetOperator(ac.OperatorID())
+	if err != nil {
+		t.Fatalf("GetOperator() failed: %v", err)
+	}
+	if o.OperatorID != "OP0000000002/switched-user" {
+		t.Fatalf("request was not sent as the switched user: %s", o.OperatorID)
+	}
+
+	// re-authentication switches the user again
+	s.expireToken()
+	o, err = ac.GetOperator(ac.OperatorID())
+	if err != nil {
+		t.Fatalf("GetOperator() failed: %v", err)
+	}
+	if o.OperatorID != "OP0000000002/switched-user" || len(s.switchBodies) != 2 {
+		t.Fatalf("user was not switched again after re-authentication: %s, %+v", o.OperatorID, s.switchBodies)
+	}
+}
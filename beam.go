@@ -0,0 +1,140 @@
+package soracom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidBeamEntryPoint is returned if the path of a SORACOM Beam HTTP or webhook entry point is missing
+var ErrInvalidBeamEntryPoint = errors.New("invalid beam entry point")
+
+// Keys of SORACOM Beam entry points in the SoracomBeam namespace of a group configuration
+const (
+	// BeamEntryPointTCP is the key of the TCP entry point
+	BeamEntryPointTCP = "tcp://beam.soracom.io:8023"
+
+	// BeamEntryPointUDP is the key of the UDP entry point
+	BeamEntryPointUDP = "udp://beam.soracom.io:23080"
+
+	// BeamEntryPointMQTT is the key of the MQTT entry point
+	BeamEntryPointMQTT = "mqtt://beam.soracom.io:1883"
+
+	// BeamEntryPointWebsite is the key of the website entry point
+	BeamEntryPointWebsite = "http://beam.soracom.io:18080"
+
+	// beamHTTPEntryPointBase is prepended to paths of HTTP entry points
+	beamHTTPEntryPointBase = "http://beam.soracom.io:8888"
+
+	// beamWebhookEntryPointBase is prepended to paths of webhook entry points. Webhooks are served on the same
+	// port as HTTP entry points but are keyed with their own scheme, so both can be set for the same path.
+	beamWebhookEntryPointBase = "webhook://beam.soracom.io:8888"
+)
+
+// joinEntryPointPath appends the path to the base of an entry point key, adding the leading "/" if missing
+func joinEntryPointPath(base, path string) string {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return base + path
+}
+
+// BeamHTTPEntryPoint returns the key of the HTTP entry point for requests to the path, e.g. "/" for all requests
+func BeamHTTPEntryPoint(path string) string {
+	return joinEntryPointPath(beamHTTPEntryPointBase, path)
+}
+
+// BeamWebhookEntryPoint returns the key of the webhook entry point for requests to the path, e.g. "/" for all requests
+func BeamWebhookEntryPoint(path string) string {
+	return joinEntryPointPath(beamWebhookEntryPointBase, path)
+}
+
+// beamPathEntryPoint returns the key built by entryPoint for the path, requiring the path to be given
+func beamPathEntryPoint(entryPoint func(string) string, path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("%w: path is required, e.g. \"/\" for all requests", ErrInvalidBeamEntryPoint)
+	}
+	return entryPoint(path), nil
+}
+
+// decodeConfigValue converts a configuration value decoded as generic JSON into v
+func decodeConfigValue(value interface{}, v interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// beamConfig decodes the configuration of the Beam entry point into v. The error matches ErrNotFound if the entry point is not configured.
+func (g *Group) beamConfig(entryPoint string, v interface{}) error {
+	beam, _ := g.Configuration[ConfigNamespaceBeam].(map[string]interface{})
+	value, ok := beam[entryPoint]
+	if !ok {
+		return fmt.Errorf("%w: beam entry point %q is not configured for group %s", ErrNotFound, entryPoint, g.GroupID)
+	}
+	return decodeConfigValue(value, v)
+}
+
+// BeamTCPConfig returns the configuration of the TCP entry point, as updated by UpdateBeamTCPEntryPointConfig
+func (g *Group) BeamTCPConfig() (*BeamTCPConfig, error) {
+	var c BeamTCPConfig
+	if err := g.beamConfig(BeamEntryPointTCP, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// BeamUDPConfig returns the configuration of the UDP entry point, as updated by UpdateBeamUDPConfig
+func (g *Group) BeamUDPConfig() (*BeamUDPConfig, error) {
+	var c BeamUDPConfig
+	if err := g.beamConfig(BeamEntryPointUDP, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// BeamMQTTConfig returns the configuration of the MQTT entry point, as updated by UpdateBeamMQTTConfig
+func (g *Group) BeamMQTTConfig() (*BeamMQTTConfig, error) {
+	var c BeamMQTTConfig
+	if err := g.beamConfig(BeamEntryPointMQTT, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// BeamHTTPConfig returns the configuration of the HTTP entry point for the path, as updated by UpdateBeamHTTPConfig
+func (g *Group) BeamHTTPConfig(path string) (*BeamHTTPConfig, error) {
+	entryPoint, err := beamPathEntryPoint(BeamHTTPEntryPoint, path)
+	if err != nil {
+		return nil, err
+	}
+	var c BeamHTTPConfig
+	if err := g.beamConfig(entryPoint, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// BeamWebsiteConfig returns the configuration of the website entry point, as updated by UpdateBeamWebsiteConfig
+func (g *Group) BeamWebsiteConfig() (*BeamWebsiteConfig, error) {
+	var c BeamWebsiteConfig
+	if err := g.beamConfig(BeamEntryPointWebsite, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// BeamWebhookConfig returns the configuration of the webhook entry point for the path, as updated by UpdateBeamWebhookConfig
+func (g *Group) BeamWebhookConfig(path string) (*BeamWebhookConfig, error) {
+	entryPoint, err := beamPathEntryPoint(BeamWebhookEntryPoint, path)
+	if err != nil {
+		return nil, err
+	}
+	var c BeamWebhookConfig
+	if err := g.beamConfig(entryPoint, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,239 @@
+package soracom
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeGroupServer keeps groups like the API does and records requests which change them
+type fakeGroupServer struct {
+	t      *testing.T
+	mu     sync.Mutex
+	groups map[string]*Group
+	nextID int
+	calls  []string
+
+	// failPath makes requests to the path fail with 400
+	failPath string
+}
+
+func (s *fakeGroupServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// /v1/groups[/{id}[/configuration/{namespace}[/{key}] | /tags[/{name}]]]
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.EscapedPath(), "/v1/groups"), "/", 5)
+	if r.Method != "GET" {
+		s.calls = append(s.calls, r.Method+" "+r.URL.EscapedPath())
+	}
+	if r.URL.EscapedPath() == s.failPath {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"code":"GRP0002","message":"invalid configuration"}`))
+		return
+	}
+	var g *Group
+	if len(parts) > 1 {
+		g = s.groups[parts[1]]
+		if g == nil && !(r.Method == "POST" && parts[1] == "") {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"GRP0001","message":"group not found"}`))
+			return
+		}
+	}
+
+	switch {
+	case r.Method == "POST" && len(parts) == 1:
+		var req createGroupRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		s.nextID++
+		g = &Group{GroupID: "group-" + string(rune('0'+s.nextID)), OperatorID: "OP0000000000", Tags: req.Tags, Configuration: map[ConfigNamespace]interface{}{}}
+		if g.Tags == nil {
+			g.Tags = Tags{}
+		}
+		s.groups[g.GroupID] = g
+	case r.Method == "DELETE" && len(parts) == 2:
+		delete(s.groups, g.GroupID)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case r.Method == "PUT" && len(parts) == 4 && parts[2] == "configuration":
+		var configs []GroupConfig
+		if err := json.NewDecoder(r.Body).Decode(&configs); err != nil {
+			s.t.Errorf("invalid request body: %v", err)
+		}
+		ns := ConfigNamespace(parts[3])
+		m, _ := g.Configuration[ns].(map[string]interface{})
+		if m == nil {
+			m = map[string]interface{}{}
+			g.Configuration[ns] = m
+		}
+		for _, c := range configs {
+			var v interface{}
+			_ = decodeConfigValue(c.Value, &v)
+			m[c.Key] = v
+		}
+	case r.Method == "DELETE" && len(parts) == 5 && parts[2] == "configuration":
+		ns := ConfigNamespace(parts[3])
+		key, _ := url.PathUnescape(parts[4])
+		m, _ := g.Configuration[ns].(map[string]interface{})
+		delete(m, key)
+		if len(m) == 0 {
+			delete(g.Configuration, ns)
+		}
+	case r.Method == "PUT" && len(parts) == 3 && parts[2] == "tags":
+		var tags []Tag
+		_ = json.NewDecoder(r.Body).Decode(&tags)
+		for _, tag := range tags {
+			g.Tags[tag.TagName] = tag.TagValue
+		}
+	case r.Method == "DELETE" && len(parts) == 4 && parts[2] == "tags":
+		name, _ := url.PathUnescape(parts[3])
+		delete(g.Tags, name)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(g)
+}
+
+// newFakeGroupServer returns a client for a fakeGroupServer holding groups
+func newFakeGroupServer(t *testing.T, groups ...*Group) (*APIClient, *fakeGroupServer) {
+	s := &fakeGroupServer{t: t, groups: map[string]*Group{}}
+	for _, g := range groups {
+		if g.Configuration == nil {
+			g.Configuration = map[ConfigNamespace]interface{}{}
+		}
+		if g.Tags == nil {
+			g.Tags = Tags{}
+		}
+		s.groups[g.GroupID] = g
+	}
+	return newIteratorTestClient(t, s), s
+}
+
+func TestBeamEntryPoints(t *testing.T) {
+	if BeamHTTPEntryPoint("/") != "http://beam.soracom.io:8888/" || BeamHTTPEntryPoint("foo") != "http://beam.soracom.io:8888/foo" {
+		t.Fatalf("unexpected HTTP entry point: %s", BeamHTTPEntryPoint("foo"))
+	}
+	if BeamWebhookEntryPoint("/") == BeamHTTPEntryPoint("/") {
+		t.Fatalf("webhook and HTTP entry points must not share a key: %s", BeamWebhookEntryPoint("/"))
+	}
+
+	ac, _ := newFakeGroupServer(t, &Group{GroupID: "group-1"})
+
+	httpConfig := &BeamHTTPConfig{
+		Name:          "http",
+		Destination:   "https://example.com/path",
+		Enabled:       true,
+		AddSignature:  true,
+		CustomHeaders: map[string]CustomHeader{"h1": {Action: "APPEND", Key: "X-Foo", Value: "bar"}},
+		PSK:           "secret",
+	}
+	mqttConfig := &BeamMQTTConfig{
+		Name:                  "mqtt",
+		Destination:           "mqtts://mqtt.example.com:8883",
+		Enabled:               true,
+		UseClientCertificates: "true",
+		ClientCertificates:    map[string]ClientCerts{"default": {CA: "ca", Cert: "cert", PrivateKey: "key"}},
+	}
+	udpConfig := &BeamUDPConfig{Name: "udp", Destination: "https://example.com/udp", Enabled: true}
+	websiteConfig := &BeamWebsiteConfig{Name: "website", Destination: "https://example.com", Enabled: true, Username: "u", Password: "p"}
+	webhookConfig := &BeamWebhookConfig{Name: "webhook", Destination: "https://example.com/hook", Enabled: true, ContentType: "application/json"}
+
+	if _, err := ac.UpdateBeamHTTPConfig("group-1", "/", httpConfig); err != nil {
+		t.Fatalf("UpdateBeamHTTPConfig() failed: %v", err)
+	}
+	if _, err := ac.UpdateBeamMQTTConfig("group-1", mqttConfig); err != nil {
+		t.Fatalf("UpdateBeamMQTTConfig() failed: %v", err)
+	}
+	if _, err := ac.UpdateBeamUDPConfig("group-1", udpConfig); err != nil {
+		t.Fatalf("UpdateBeamUDPConfig() failed: %v", err)
+	}
+	if _, err := ac.UpdateBeamWebsiteConfig("group-1", websiteConfig); err != nil {
+		t.Fatalf("UpdateBeamWebsiteConfig() failed: %v", err)
+	}
+	g, err := ac.UpdateBeamWebhookConfig("group-1", "/", webhookConfig)
+	if err != nil {
+		t.Fatalf("UpdateBeamWebhookConfig() failed: %v", err)
+	}
+
+	beam := g.Configuration["SoracomBeam"].(map[string]interface{})
+	for _, key := range []string{"http://beam.soracom.io:8888/", "mqtt://beam.soracom.io:1883", "udp://beam.soracom.io:23080", "http://beam.soracom.io:18080", "webhook://beam.soracom.io:8888/"} {
+		if _, ok := beam[key]; !ok {
+			t.Fatalf("entry point %s not found in %v", key, beam)
+		}
+	}
+
+	h, err := g.BeamHTTPConfig("/")
+	if err != nil || !reflect.DeepEqual(h, httpConfig) {
+		t.Fatalf("unexpected HTTP config: %+v, %v", h, err)
+	}
+	m, err := g.BeamMQTTConfig()
+	if err != nil || !reflect.DeepEqual(m, mqttConfig) {
+		t.Fatalf("unexpected MQTT config: %+v, %v", m, err)
+	}
+	u, err := g.BeamUDPConfig()
+	if err != nil || !reflect.DeepEqual(u, udpConfig) {
+		t.Fatalf("unexpected UDP config: %+v, %v", u, err)
+	}
+	ws, err := g.BeamWebsiteConfig()
+	if err != nil || !reflect.DeepEqual(ws, websiteConfig) {
+		t.Fatalf("unexpected website config: %+v, %v", ws, err)
+	}
+	wh, err := g.BeamWebhookConfig("/")
+	if err != nil || !reflect.DeepEqual(wh, webhookConfig) {
+		t.Fatalf("unexpected webhook config: %+v, %v", wh, err)
+	}
+
+	_, err = g.BeamTCPConfig()
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an entry point not configured: %v", err)
+	}
+	_, err = (&Group{}).BeamHTTPConfig("/")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for a group without beam configuration: %v", err)
+	}
+
+	_, err = ac.UpdateBeamHTTPConfig("group-1", "", httpConfig)
+	if !errors.Is(err, ErrInvalidBeamEntryPoint) {
+		t.Fatalf("expected ErrInvalidBeamEntryPoint for an HTTP entry point without a path: %v", err)
+	}
+	_, err = ac.UpdateBeamWebhookConfig("group-1", "", webhookConfig)
+	if !errors.Is(err, ErrInvalidBeamEntryPoint) {
+		t.Fatalf("expected ErrInvalidBeamEntryPoint for a webhook entry point without a path: %v", err)
+	}
+}
+
+func TestBeamTCPEntryPoint(t *testing.T) {
+	ac, _ := newFakeGroupServer(t, &Group{GroupID: "group-1"})
+	tcpConfig := &BeamTCPConfig{Name: "tcp", Destination: "tcps://tcp.example.com:1234", Enabled: true, AddSubscriberHeader: true}
+
+	g, err := ac.UpdateBeamTCPEntryPointConfig("group-1", tcpConfig)
+	if err != nil {
+		t.Fatalf("UpdateBeamTCPEntryPointConfig() failed: %v", err)
+	}
+	beam := g.Configuration["SoracomBeam"].(map[string]interface{})
+	if _, ok := beam["tcp://beam.soracom.io:8023"]; !ok {
+		t.Fatalf("TCP entry point not found in %v", beam)
+	}
+	c, err := g.BeamTCPConfig()
+	if err != nil || !reflect.DeepEqual(c, tcpConfig) {
+		t.Fatalf("unexpected TCP config: %+v, %v", c, err)
+	}
+
+	if _, err := ac.UpdateBeamTCPConfig("group-1", BeamEntryPointTCP, tcpConfig); err != nil {
+		t.Fatalf("UpdateBeamTCPConfig() failed for the TCP entry point: %v", err)
+	}
+	_, err = ac.UpdateBeamTCPConfig("group-1", BeamEntryPointUDP, tcpConfig)
+	if !errors.Is(err, ErrInvalidBeamEntryPoint) {
+		t.Fatalf("expected ErrInvalidBeamEntryPoint for a key other than the TCP entry point: %v", err)
+	}
+}
@@ -0,0 +1,295 @@
+package soracom
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"sync"
+)
+
+// defaultBulkConcurrency is the number of operations run at once if BulkOptions.Concurrency is not set
+const defaultBulkConcurrency = 4
+
+// ErrSkipSubscriber can be returned by a BulkOperation to report that the subscriber needs no change
+var ErrSkipSubscriber = errors.New("subscriber skipped")
+
+// ErrAlreadyProcessed is the reason for subscribers skipped because the checkpoint file records them as processed
+var ErrAlreadyProcessed = errors.New("subscriber already processed in a previous run")
+
+// BulkOperation is an action on a subscriber run by RunBulk, e.g.
+//
+//	func(ctx context.Context, imsi string) error {
+//		_, err := client.ActivateSubscriberWithContext(ctx, imsi)
+//		return err
+//	}
+type BulkOperation func(ctx context.Context, imsi string) error
+
+// SubscriberSource yields subscribers to RunBulk. SubscriberIterator and SubscriberStream are SubscriberSources.
+type SubscriberSource interface {
+	Next() bool
+	Value() Subscriber
+	Err() error
+}
+
+// imsiSource is a SubscriberSource over a list of IMSIs
+type imsiSource struct {
+	imsis []string
+	i     int
+}
+
+// IMSIs returns a SubscriberSource yielding subscribers with the IMSIs
+func IMSIs(imsis ...string) SubscriberSource {
+	return &imsiSource{imsis: imsis, i: -1}
+}
+
+func (s *imsiSource) Next() bool {
+	s.i++
+	return s.i < len(s.imsis)
+}
+
+func (s *imsiSource) Value() Subscriber {
+	return Subscriber{IMSI: s.imsis[s.i]}
+}
+
+func (s *imsiSource) Err() error {
+	return nil
+}
+
+// BulkOptions holds options for RunBulk
+type BulkOptions struct {
+	// Concurrency is the maximum number of operations run at once. Defaults to 4.
+	Concurrency int
+
+	// RateLimiter limits the rate of operations in addition to the rate limits of the client, if not nil
+	RateLimiter RateLimiter
+
+	// CheckpointFile is the path of a file which records processed subscribers.
+	// Subscribers recorded as succeeded or skipped are skipped when RunBulk is run again with the same file, so that an interrupted run can be resumed.
+	CheckpointFile string
+}
+
+// BulkResultStatus is the outcome of an operation on a subscriber
+type BulkResultStatus string
+
+const (
+	// BulkResultSucceeded means the operation succeeded
+	BulkResultSucceeded BulkResultStatus = "succeeded"
+
+	// BulkResultFailed means the operation returned an error
+	BulkResultFailed BulkResultStatus = "failed"
+
+	// BulkResultSkipped means the operation was not run or returned ErrSkipSubscriber
+	BulkResultSkipped BulkResultStatus = "skipped"
+)
+
+// BulkResult is the result of an operation on a subscriber
+type BulkResult struct {
+	IMSI   string
+	Status BulkResultStatus
+
+	// Err is the error returned by the operation for failed results, e.g. an *APIError, or the reason for skipped results
+	Err error
+}
+
+// BulkReport holds results for all subscribers in the order they were yielded by the source
+type BulkReport struct {
+	Results []BulkResult
+}
+
+// Count returns the number of results with the status
+func (r *BulkReport) Count(status BulkResultStatus) int {
+	n := 0
+	for _, result := range r.Results {
+		if result.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Failed returns the failed results
+func (r *BulkReport) Failed() []BulkResult {
+	var failed []BulkResult
+	for _, result := range r.Results {
+		if result.Status == BulkResultFailed {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+type checkpointRecord struct {
+	IMSI   string           `json:"imsi"`
+	Status BulkResultStatus `json:"status"`
+	Error  string           `json:"error,omitempty"`
+}
+
+// checkpoint appends results to a file as JSON lines
+type checkpoint struct {
+	mu   sync.Mutex
+	f    *os.File
+	done map[string]bool
+}
+
+func openCheckpoint(path string) (*checkpoint, error) {
+	c := &checkpoint{done: map[string]bool{}}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var r checkpointRecord
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			// the last line may have been written partially when the previous run crashed
+			continue
+		}
+		c.done[r.IMSI] = r.Status != BulkResultFailed
+	}
+	if err := sc.Err(); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	// terminate a partially written line so that it does not swallow the next record
+	if info, err := f.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			if _, err := f.Write([]byte{'\n'}); err != nil {
+				f.Close()
+				return nil, err
+			}
+		}
+	}
+
+	c.f = f
+	return c, nil
+}
+
+func (c *checkpoint) record(r BulkResult) error {
+	rec := checkpointRecord{IMSI: r.IMSI, Status: r.Status}
+	if r.Status == BulkResultFailed {
+		rec.Error = r.Err.Error()
+	}
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := c.f.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	return c.f.Sync()
+}
+
+// RunBulk runs op on every subscriber yielded by src with bounded concurrency and returns a report of the results.
+// Errors returned by op are reported per subscriber; RunBulk itself fails only if src, ctx or the checkpoint file fails,
+// in which case the report holds the results so far.
+func RunBulk(ctx context.Context, src SubscriberSource, op BulkOperation, options *BulkOptions) (*BulkReport, error) {
+	concurrency := defaultBulkConcurrency
+	var limiter RateLimiter
+	var cp *checkpoint
+	if options != nil {
+		if options.Concurrency > 0 {
+			concurrency = options.Concurrency
+		}
+		limiter = options.RateLimiter
+		if options.CheckpointFile != "" {
+			var err error
+			cp, err = openCheckpoint(options.CheckpointFile)
+			if err != nil {
+				return nil, err
+			}
+			defer cp.f.Close()
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type indexedResult struct {
+		i int
+		BulkResult
+	}
+	var (
+		mu       sync.Mutex
+		results  []indexedResult
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	report := func(i int, r BulkResult) {
+		// subscribers skipped without running op are not recorded so that they are processed in the next run,
+		// nor are subscribers without a session as they may be online by then
+		var err error
+		if cp != nil && (r.Status != BulkResultSkipped || errors.Is(r.Err, ErrSkipSubscriber)) && !errors.Is(r.Err, ErrNoSession) {
+			err = cp.record(r)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		results = append(results, indexedResult{i, r})
+		if err != nil && firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	sem := make(chan struct{}, concurrency)
+	i := 0
+	for ; src.Next(); i++ {
+		imsi := src.Value().IMSI
+		if cp != nil && cp.done[imsi] {
+			report(i, BulkResult{IMSI: imsi, Status: BulkResultSkipped, Err: ErrAlreadyProcessed})
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() == nil && limiter != nil {
+			_ = limiter.Wait(ctx)
+		}
+		if ctx.Err() != nil {
+			report(i, BulkResult{IMSI: imsi, Status: BulkResultSkipped, Err: ctx.Err()})
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, imsi string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			err := op(ctx, imsi)
+			switch {
+			case err == nil:
+				report(i, BulkResult{IMSI: imsi, Status: BulkResultSucceeded})
+			case errors.Is(err, ErrSkipSubscriber):
+				report(i, BulkResult{IMSI: imsi, Status: BulkResultSkipped, Err: err})
+			default:
+				report(i, BulkResult{IMSI: imsi, Status: BulkResultFailed, Err: err})
+			}
+		}(i, imsi)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(a, b int) bool { return results[a].i < results[b].i })
+	r := &BulkReport{Results: make([]BulkResult, 0, len(results))}
+	for _, result := range results {
+		r.Results = append(r.Results, result.BulkResult)
+	}
+
+	if firstErr != nil {
+		return r, firstErr
+	}
+	if err := src.Err(); err != nil {
+		return r, err
+	}
+	return r, ctx.Err()
+}
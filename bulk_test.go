@@ -0,0 +1,120 @@
+package soracom
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunBulk(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	op := func(ctx context.Context, imsi string) error {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		switch imsi {
+		case "002":
+			return &APIError{HTTPStatusCode: 400, ErrorCode: "SEM0001", Message: "bad request"}
+		case "003":
+			return ErrSkipSubscriber
+		}
+		return nil
+	}
+	limiter := &countingRateLimiter{}
+
+	report, err := RunBulk(context.Background(), IMSIs("001", "002", "003", "004", "005", "006"), op, &BulkOptions{Concurrency: 2, RateLimiter: limiter})
+	if err != nil {
+		t.Fatalf("RunBulk() failed: %v", err)
+	}
+	if maxInFlight > 2 {
+		t.Fatalf("concurrency was not bounded: %d", maxInFlight)
+	}
+	if limiter.count != 6 {
+		t.Fatalf("rate limiter was not used: %d", limiter.count)
+	}
+	var statuses []string
+	for _, r := range report.Results {
+		statuses = append(statuses, r.IMSI+":"+string(r.Status))
+	}
+	if strings.Join(statuses, ",") != "001:succeeded,002:failed,003:skipped,004:succeeded,005:succeeded,006:succeeded" {
+		t.Fatalf("unexpected results: %v", statuses)
+	}
+	var apiErr *APIError
+	if failed := report.Failed(); len(failed) != 1 || !errors.As(failed[0].Err, &apiErr) || apiErr.ErrorCode != "SEM0001" {
+		t.Fatalf("unexpected failed results: %+v", failed)
+	}
+	if report.Count(BulkResultSucceeded) != 4 || report.Count(BulkResultSkipped) != 1 {
+		t.Fatalf("unexpected counts: %+v", report.Results)
+	}
+}
+
+func TestRunBulkResume(t *testing.T) {
+	checkpointFile := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+	imsis := []string{"001", "002", "003", "004", "005"}
+
+	// the first run fails for 002 and is interrupted at 004
+	ctx, cancel := context.WithCancel(context.Background())
+	var processed []string
+	report, err := RunBulk(ctx, IMSIs(imsis...), func(ctx context.Context, imsi string) error {
+		processed = append(processed, imsi)
+		switch imsi {
+		case "002":
+			return errors.New("temporary failure")
+		case "003":
+			cancel()
+		}
+		return nil
+	}, &BulkOptions{Concurrency: 1, CheckpointFile: checkpointFile})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(report.Results) != 4 || report.Results[3].Status != BulkResultSkipped {
+		t.Fatalf("unexpected results: %+v", report.Results)
+	}
+
+	// simulate a crash while writing a record
+	f, err := os.OpenFile(checkpointFile, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"imsi":"00`)
+	f.Close()
+
+	processed = nil
+	report, err = RunBulk(context.Background(), IMSIs(imsis...), func(ctx context.Context, imsi string) error {
+		processed = append(processed, imsi)
+		return nil
+	}, &BulkOptions{Concurrency: 1, CheckpointFile: checkpointFile})
+	if err != nil {
+		t.Fatalf("RunBulk() failed: %v", err)
+	}
+	if strings.Join(processed, ",") != "002,004,005" {
+		t.Fatalf("only unprocessed subscribers should be processed: %v", processed)
+	}
+	if report.Count(BulkResultSkipped) != 2 || !errors.Is(report.Results[0].Err, ErrAlreadyProcessed) {
+		t.Fatalf("unexpected results: %+v", report.Results)
+	}
+
+	// all subscribers have been processed
+	report, err = RunBulk(context.Background(), IMSIs(imsis...), func(ctx context.Context, imsi string) error {
+		t.Errorf("%s should not be processed again", imsi)
+		return nil
+	}, &BulkOptions{CheckpointFile: checkpointFile})
+	if err != nil || report.Count(BulkResultSkipped) != 5 {
+		t.Fatalf("unexpected results: %+v, %v", report.Results, err)
+	}
+}
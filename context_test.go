@@ -0,0 +1,68 @@
+package soracom
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newBlockingServer(t *testing.T) (*httptest.Server, chan struct{}) {
+	started := make(chan struct{}, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts, started
+}
+
+func TestAPIClientCancelInFlightRequest(t *testing.T) {
+	ts, started := newBlockingServer(t)
+	ac := NewAPIClient(&APIClientOptions{Endpoint: ts.URL})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	_, _, err := ac.ListSubscribersWithContext(ctx, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestAPIClientDeadlineExceeded(t *testing.T) {
+	ts, _ := newBlockingServer(t)
+	ac := NewAPIClient(&APIClientOptions{Endpoint: ts.URL})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := ac.GetSubscriberWithContext(ctx, "001010000000001")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestMetadataClientCancelInFlightRequest(t *testing.T) {
+	ts, started := newBlockingServer(t)
+	mc := NewMetadataClient(&MetadataClientOptions{Endpoint: ts.URL})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	_, err := mc.GetSubscriberWithContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
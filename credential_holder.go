@@ -0,0 +1,46 @@
+package soracom
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// authState is an immutable snapshot of the credentials used by an APIClient.
+type authState struct {
+	apiKey     string
+	token      string
+	operatorID string
+	userName   string
+	expiresAt  time.Time
+
+	// authRequest holds the credentials the client authenticated with. It is nil if the client cannot authenticate by itself.
+	authRequest *AuthRequest
+
+	// switchUser holds the user the client has switched to after authentication, if any.
+	switchUser *switchUserRequest
+}
+
+// credentialHolder holds the current authState.
+// Readers never block: every update atomically swaps the whole snapshot so that an API key and a token from different authentications are never mixed.
+type credentialHolder struct {
+	writeMu sync.Mutex
+	state   atomic.Value
+}
+
+func (h *credentialHolder) load() *authState {
+	s, _ := h.state.Load().(*authState)
+	if s == nil {
+		return &authState{}
+	}
+	return s
+}
+
+// update replaces the current state with the one returned by f. Concurrent updates are serialized.
+func (h *credentialHolder) update(f func(s authState) authState) {
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+
+	next := f(*h.load())
+	h.state.Store(&next)
+}
@@ -0,0 +1,135 @@
+package soracom
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestListSubscribersWhileReauthenticating(t *testing.T) {
+	var authCount int32
+	var mismatches int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1/auth" {
+			n := atomic.AddInt32(&authCount, 1)
+			_, _ = w.Write([]byte(toJSON(&AuthResponse{
+				APIKey:     fmt.Sprintf("api-key-%d", n),
+				Token:      fmt.Sprintf("token-%d", n),
+				OperatorID: "OP0000000000",
+			})))
+			return
+		}
+
+		// an API key and a token must come from the same authentication
+		apiKey := r.Header.Get("X-Soracom-API-Key")
+		token := r.Header.Get("X-Soracom-Token")
+		if strings.TrimPrefix(apiKey, "api-key-") != strings.TrimPrefix(token, "token-") {
+			atomic.AddInt32(&mismatches, 1)
+		}
+		_, _ = w.Write([]byte(`[{"imsi":"001010000000001"}]`))
+	}))
+	defer ts.Close()
+
+	ac := NewAPIClient(&APIClientOptions{Endpoint: ts.URL})
+	err := ac.Auth("test@example.com", "password")
+	if err != nil {
+		t.Fatalf("Auth() failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				_, _, err := ac.ListSubscribers(nil)
+				if err != nil {
+					t.Errorf("ListSubscribers() failed: %v", err)
+					return
+				}
+				_ = ac.OperatorID()
+			}
+		}()
+	}
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				err := ac.Auth("test@example.com", "password")
+				if err != nil {
+					t.Errorf("Auth() failed: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if mismatches != 0 {
+		t.Fatalf("%d requests were sent with an API key and a token from different authentications", mismatches)
+	}
+	if strings.TrimPrefix(ac.APIKey(), "api-key-") != strings.TrimPrefix(ac.Token(), "token-") {
+		t.Fatalf("unexpected credentials: %s, %s", ac.APIKey(), ac.Token())
+	}
+}
+
+func TestSetAuthInfo(t *testing.T) {
+	ac := NewAPIClient(nil)
+	ac.SetAuthInfo("api-key", "token", "OP0000000000")
+	if ac.APIKey() != "api-key" || ac.Token() != "token" || ac.OperatorID() != "OP0000000000" {
+		t.Fatalf("unexpected credentials: %s, %s, %s", ac.APIKey(), ac.Token(), ac.OperatorID())
+	}
+	if ac.canReauth() {
+		t.Fatal("client should not be able to authenticate by itself")
+	}
+}
+
+func TestSetVerboseWhileCallingAPIs(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"imsi":"001010000000001"}`))
+	}))
+	defer ts.Close()
+
+	l := &recordingLogger{}
+	ac := NewAPIClient(&APIClientOptions{Endpoint: ts.URL, Logger: l})
+	mc := NewMetadataClient(&MetadataClientOptions{Endpoint: ts.URL, Logger: l})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if _, err := ac.GetSubscriber("001010000000001"); err != nil {
+					t.Errorf("GetSubscriber() failed: %v", err)
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if _, err := mc.GetSubscriber(); err != nil {
+					t.Errorf("GetSubscriber() failed: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 100; j++ {
+			ac.SetVerbose(j%2 == 0)
+			mc.SetVerbose(j%2 == 0)
+		}
+	}()
+	wg.Wait()
+}
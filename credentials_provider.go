@@ -0,0 +1,259 @@
+package soracom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrNoCredentials is returned by a CredentialsProvider which has no credentials to provide
+var ErrNoCredentials = errors.New("no credentials found")
+
+// AuthCredentials holds credentials to access SORACOM API.
+// One of AuthKeyID/AuthKey, Email/Password, OperatorID/UserName/Password (SAM user) or APIKey/Token has to be set.
+type AuthCredentials struct {
+	AuthKeyID string
+	AuthKey   string
+
+	Email    string
+	UserName string
+	Password string
+
+	APIKey     string
+	Token      string
+	OperatorID string
+
+	CoverageType CoverageType
+	Sandbox      bool
+
+	// Endpoint overrides the API endpoint if not empty
+	Endpoint string
+}
+
+func (c *AuthCredentials) hasCredentials() bool {
+	return (c.AuthKeyID != "" && c.AuthKey != "") ||
+		(c.Email != "" && c.Password != "") ||
+		(c.OperatorID != "" && c.UserName != "" && c.Password != "") ||
+		(c.APIKey != "" && c.Token != "")
+}
+
+// CredentialsProvider provides credentials to access SORACOM API
+type CredentialsProvider interface {
+	// Retrieve returns credentials, or ErrNoCredentials if the provider has none.
+	Retrieve() (*AuthCredentials, error)
+}
+
+// StaticCredentialsProvider provides credentials specified explicitly
+type StaticCredentialsProvider struct {
+	Credentials AuthCredentials
+}
+
+// Retrieve returns the explicitly specified credentials
+func (p *StaticCredentialsProvider) Retrieve() (*AuthCredentials, error) {
+	if !p.Credentials.hasCredentials() {
+		return nil, ErrNoCredentials
+	}
+	c := p.Credentials
+	return &c, nil
+}
+
+// EnvCredentialsProvider provides credentials from environment variables.
+// SORACOM_AUTH_KEY_ID/SORACOM_AUTH_KEY, SORACOM_EMAIL/SORACOM_PASSWORD, SORACOM_OPERATOR_ID/SORACOM_USERNAME/SORACOM_PASSWORD
+// and SORACOM_API_KEY/SORACOM_TOKEN/SORACOM_OPERATOR_ID are read as credentials,
+// SORACOM_COVERAGE_TYPE and SORACOM_ENDPOINT are read to select the API endpoint.
+type EnvCredentialsProvider struct{}
+
+// Retrieve returns credentials read from environment variables
+func (p *EnvCredentialsProvider) Retrieve() (*AuthCredentials, error) {
+	c := &AuthCredentials{
+		AuthKeyID:    os.Getenv("SORACOM_AUTH_KEY_ID"),
+		AuthKey:      os.Getenv("SORACOM_AUTH_KEY"),
+		Email:        os.Getenv("SORACOM_EMAIL"),
+		UserName:     os.Getenv("SORACOM_USERNAME"),
+		Password:     os.Getenv("SORACOM_PASSWORD"),
+		APIKey:       os.Getenv("SORACOM_API_KEY"),
+		Token:        os.Getenv("SORACOM_TOKEN"),
+		OperatorID:   os.Getenv("SORACOM_OPERATOR_ID"),
+		CoverageType: CoverageType(os.Getenv("SORACOM_COVERAGE_TYPE")),
+		Endpoint:     os.Getenv("SORACOM_ENDPOINT"),
+	}
+	if !c.hasCredentials() {
+		return nil, ErrNoCredentials
+	}
+	return c, nil
+}
+
+// Profile represents a profile file of soracom-cli
+type Profile struct {
+	Sandbox      bool    `json:"sandbox"`
+	CoverageType string  `json:"coverageType"`
+	Email        *string `json:"email"`
+	OperatorID   *string `json:"operatorId"`
+	Username     *string `json:"username"`
+	Password     *string `json:"password"`
+	AuthKeyID    *string `json:"authKeyId"`
+	AuthKey      *string `json:"authKey"`
+	Endpoint     *string `json:"endpoint"`
+}
+
+// ProfileCredentialsProvider provides credentials from a soracom-cli compatible profile file (<Dir>/<Profile>.json).
+type ProfileCredentialsProvider struct {
+	// Profile is the name of the profile. Defaults to $SORACOM_PROFILE, or "default" if it is not set.
+	Profile string
+
+	// Dir is the directory which contains profile files. Defaults to $SORACOM_PROFILE_DIR, or ~/.soracom if it is not set.
+	Dir string
+}
+
+// Retrieve returns credentials read from the profile file
+func (p *ProfileCredentialsProvider) Retrieve() (*AuthCredentials, error) {
+	path, err := p.path()
+	if err != nil {
+		return nil, err
+	}
+
+	prof, err := readProfile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &AuthCredentials{
+		AuthKeyID:    stringValue(prof.AuthKeyID),
+		AuthKey:      stringValue(prof.AuthKey),
+		Email:        stringValue(prof.Email),
+		OperatorID:   stringValue(prof.OperatorID),
+		UserName:     stringValue(prof.Username),
+		Password:     stringValue(prof.Password),
+		CoverageType: CoverageType(prof.CoverageType),
+		Sandbox:      prof.Sandbox,
+		Endpoint:     stringValue(prof.Endpoint),
+	}
+	if !c.hasCredentials() {
+		return nil, fmt.Errorf("profile %s does not contain credentials", path)
+	}
+	return c, nil
+}
+
+func (p *ProfileCredentialsProvider) path() (string, error) {
+	name := p.Profile
+	if name == "" {
+		name = os.Getenv("SORACOM_PROFILE")
+	}
+	if name == "" {
+		name = "default"
+	}
+
+	dir := p.Dir
+	if dir == "" {
+		dir = os.Getenv("SORACOM_PROFILE_DIR")
+	}
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".soracom")
+	}
+
+	return filepath.Join(dir, name+".json"), nil
+}
+
+func readProfile(path string) (*Profile, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var prof Profile
+	err = json.Unmarshal(b, &prof)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse profile %s: %w", path, err)
+	}
+	return &prof, nil
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// ChainCredentialsProvider tries each provider in order and returns the first credentials found
+type ChainCredentialsProvider []CredentialsProvider
+
+// Retrieve returns credentials from the first provider which has any
+func (c ChainCredentialsProvider) Retrieve() (*AuthCredentials, error) {
+	for _, p := range c {
+		creds, err := p.Retrieve()
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		return creds, err
+	}
+	return nil, ErrNoCredentials
+}
+
+// NewDefaultCredentialsProvider returns a CredentialsProvider which looks for credentials in the following order:
+// explicit (if not nil), environment variables and the soracom-cli profile selected by $SORACOM_PROFILE.
+func NewDefaultCredentialsProvider(explicit *AuthCredentials) CredentialsProvider {
+	chain := ChainCredentialsProvider{}
+	if explicit != nil {
+		chain = append(chain, &StaticCredentialsProvider{Credentials: *explicit})
+	}
+	return append(chain, &EnvCredentialsProvider{}, &ProfileCredentialsProvider{})
+}
+
+// NewAPIClientWithCredentials creates an instance of APIClient which uses credentials retrieved from provider.
+// The client authenticates lazily on the first API call, and again whenever its token expires.
+func NewAPIClientWithCredentials(provider CredentialsProvider, options *APIClientOptions) (*APIClient, error) {
+	creds, err := provider.Retrieve()
+	if err != nil {
+		return nil, err
+	}
+
+	var opts APIClientOptions
+	if options != nil {
+		opts = *options
+	}
+	if opts.Endpoint == "" {
+		opts.Endpoint = creds.Endpoint
+	}
+	if opts.CoverageType == "" {
+		opts.CoverageType = creds.CoverageType
+	}
+	opts.Sandbox = opts.Sandbox || creds.Sandbox
+
+	ac := NewAPIClient(&opts)
+	ac.useCredentials(creds)
+	return ac, nil
+}
+
+// NewAPIClientFromProfile creates an instance of APIClient configured with the soracom-cli profile named profileName.
+func NewAPIClientFromProfile(profileName string, options *APIClientOptions) (*APIClient, error) {
+	return NewAPIClientWithCredentials(&ProfileCredentialsProvider{Profile: profileName}, options)
+}
+
+func (ac *APIClient) useCredentials(creds *AuthCredentials) {
+	switch {
+	case creds.AuthKeyID != "" && creds.AuthKey != "":
+		ac.creds.update(func(authState) authState {
+			return authState{authRequest: &AuthRequest{AuthKeyID: creds.AuthKeyID, AuthKey: creds.AuthKey}}
+		})
+	case creds.OperatorID != "" && creds.UserName != "" && creds.Password != "":
+		ac.creds.update(func(authState) authState {
+			return authState{authRequest: &AuthRequest{OperatorID: creds.OperatorID, UserName: creds.UserName, Password: creds.Password}}
+		})
+	case creds.Email != "" && creds.Password != "":
+		ac.creds.update(func(authState) authState {
+			return authState{authRequest: &AuthRequest{Email: creds.Email, Password: creds.Password}}
+		})
+	default:
+		ac.SetAuthInfo(creds.APIKey, creds.Token, creds.OperatorID)
+	}
+}
@@ -0,0 +1,184 @@
+package soracom
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func clearCredentialsEnv(t *testing.T) {
+	for _, k := range []string{
+		"SORACOM_AUTH_KEY_ID", "SORACOM_AUTH_KEY", "SORACOM_EMAIL", "SORACOM_USERNAME", "SORACOM_PASSWORD",
+		"SORACOM_API_KEY", "SORACOM_TOKEN", "SORACOM_OPERATOR_ID", "SORACOM_COVERAGE_TYPE",
+		"SORACOM_ENDPOINT", "SORACOM_PROFILE", "SORACOM_PROFILE_DIR",
+	} {
+		t.Setenv(k, "")
+	}
+}
+
+func writeProfile(t *testing.T, dir, name, content string) {
+	err := os.WriteFile(filepath.Join(dir, name+".json"), []byte(content), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnvCredentialsProvider(t *testing.T) {
+	clearCredentialsEnv(t)
+
+	_, err := (&EnvCredentialsProvider{}).Retrieve()
+	if !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("expected ErrNoCredentials, got %v", err)
+	}
+
+	t.Setenv("SORACOM_AUTH_KEY_ID", "keyId-xxx")
+	t.Setenv("SORACOM_AUTH_KEY", "secret-xxx")
+	t.Setenv("SORACOM_COVERAGE_TYPE", "g")
+	c, err := (&EnvCredentialsProvider{}).Retrieve()
+	if err != nil {
+		t.Fatalf("Retrieve() failed: %v", err)
+	}
+	if c.AuthKeyID != "keyId-xxx" || c.AuthKey != "secret-xxx" || c.CoverageType != "g" {
+		t.Fatalf("unexpected credentials: %+v", c)
+	}
+}
+
+func TestProfileCredentialsProvider(t *testing.T) {
+	clearCredentialsEnv(t)
+	dir := t.TempDir()
+	writeProfile(t, dir, "default", `{"sandbox":false,"coverageType":"jp","email":"test@example.com","password":"p@ssw0rd","authKeyId":null,"authKey":null,"username":null,"operatorId":null,"endpoint":null,"registerPaymentMethod":true}`)
+	writeProfile(t, dir, "global", `{"coverageType":"g","authKeyId":"keyId-xxx","authKey":"secret-xxx","endpoint":"https://example.com"}`)
+	writeProfile(t, dir, "empty", `{"coverageType":"jp"}`)
+	writeProfile(t, dir, "broken", `{`)
+
+	c, err := (&ProfileCredentialsProvider{Dir: dir}).Retrieve()
+	if err != nil {
+		t.Fatalf("Retrieve() failed: %v", err)
+	}
+	if c.Email != "test@example.com" || c.Password != "p@ssw0rd" || c.CoverageType != "jp" {
+		t.Fatalf("unexpected credentials: %+v", c)
+	}
+
+	t.Setenv("SORACOM_PROFILE_DIR", dir)
+	t.Setenv("SORACOM_PROFILE", "global")
+	c, err = (&ProfileCredentialsProvider{}).Retrieve()
+	if err != nil {
+		t.Fatalf("Retrieve() failed: %v", err)
+	}
+	if c.AuthKeyID != "keyId-xxx" || c.CoverageType != "g" || c.Endpoint != "https://example.com" {
+		t.Fatalf("unexpected credentials: %+v", c)
+	}
+
+	_, err = (&ProfileCredentialsProvider{Profile: "missing"}).Retrieve()
+	if !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("expected ErrNoCredentials, got %v", err)
+	}
+	for _, name := range []string{"empty", "broken"} {
+		_, err = (&ProfileCredentialsProvider{Profile: name}).Retrieve()
+		if err == nil || errors.Is(err, ErrNoCredentials) {
+			t.Fatalf("expected an error for profile %s, got %v", name, err)
+		}
+	}
+}
+
+func TestDefaultCredentialsProvider(t *testing.T) {
+	clearCredentialsEnv(t)
+	dir := t.TempDir()
+	writeProfile(t, dir, "default", `{"coverageType":"jp","authKeyId":"keyId-profile","authKey":"secret-profile"}`)
+	t.Setenv("SORACOM_PROFILE_DIR", dir)
+
+	c, err := NewDefaultCredentialsProvider(nil).Retrieve()
+	if err != nil || c.AuthKeyID != "keyId-profile" {
+		t.Fatalf("expected credentials from profile, got %+v, %v", c, err)
+	}
+
+	t.Setenv("SORACOM_AUTH_KEY_ID", "keyId-env")
+	t.Setenv("SORACOM_AUTH_KEY", "secret-env")
+	c, err = NewDefaultCredentialsProvider(nil).Retrieve()
+	if err != nil || c.AuthKeyID != "keyId-env" {
+		t.Fatalf("expected credentials from env vars, got %+v, %v", c, err)
+	}
+
+	c, err = NewDefaultCredentialsProvider(&AuthCredentials{APIKey: "api-key", Token: "token"}).Retrieve()
+	if err != nil || c.APIKey != "api-key" {
+		t.Fatalf("expected explicit credentials, got %+v, %v", c, err)
+	}
+}
+
+func TestNewAPIClientFromProfile(t *testing.T) {
+	clearCredentialsEnv(t)
+	var authBody AuthRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/auth":
+			_ = json.NewDecoder(r.Body).Decode(&authBody)
+			_, _ = w.Write([]byte(toJSON(&AuthResponse{APIKey: "api-key", Token: "token", OperatorID: "OP0000000000"})))
+		case "/v1/operators/OP0000000000":
+			if r.Header.Get("X-Soracom-Token") != "token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"operatorId":"OP0000000000"}`))
+		}
+	}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	writeProfile(t, dir, "test", `{"coverageType":"jp","authKeyId":"keyId-xxx","authKey":"secret-xxx","endpoint":"`+ts.URL+`"}`)
+	t.Setenv("SORACOM_PROFILE_DIR", dir)
+
+	ac, err := NewAPIClientFromProfile("test", nil)
+	if err != nil {
+		t.Fatalf("NewAPIClientFromProfile() failed: %v", err)
+	}
+	if ac.endpoint != ts.URL {
+		t.Fatalf("endpoint in the profile was not used: %s", ac.endpoint)
+	}
+
+	o, err := ac.GetOperator("OP0000000000")
+	if err != nil {
+		t.Fatalf("GetOperator() failed: %v", err)
+	}
+	if o.OperatorID != "OP0000000000" || ac.OperatorID() != "OP0000000000" {
+		t.Fatalf("unexpected operator: %+v", o)
+	}
+	if authBody.AuthKeyID != "keyId-xxx" || authBody.AuthKey != "secret-xxx" {
+		t.Fatalf("credentials in the profile were not used: %+v", authBody)
+	}
+}
+
+func TestLazyAuthOperatorScopedCall(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/auth":
+			_, _ = w.Write([]byte(toJSON(&AuthResponse{APIKey: "api-key", Token: "token", OperatorID: "OP0000000000"})))
+		case "/v1/operators/OP0000000000/support/token":
+			_, _ = w.Write([]byte(`{"token":"support-token"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	provider := &StaticCredentialsProvider{Credentials: AuthCredentials{AuthKeyID: "keyId-xxx", AuthKey: "secret-xxx"}}
+	ac, err := NewAPIClientWithCredentials(provider, &APIClientOptions{Endpoint: ts.URL})
+	if err != nil {
+		t.Fatalf("NewAPIClientWithCredentials() failed: %v", err)
+	}
+
+	token, err := ac.GetSupportToken()
+	if err != nil || token != "support-token" {
+		t.Fatalf("GetSupportToken() failed: %q, %v, requests: %v", token, err, paths)
+	}
+	if len(paths) != 2 || paths[0] != "POST /v1/auth" || paths[1] != "POST /v1/operators/OP0000000000/support/token" {
+		t.Fatalf("unexpected requests: %v", paths)
+	}
+}
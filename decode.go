@@ -0,0 +1,102 @@
+package soracom
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DecodeError is returned if an API response or an item in it cannot be decoded
+type DecodeError struct {
+	// Index is the index of the item in a list response, or -1 if the response is not a list
+	Index int
+
+	// Raw is the JSON which could not be decoded
+	Raw json.RawMessage
+
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("decoding response: %v", e.Err)
+	}
+	return fmt.Sprintf("decoding item %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeErrors is returned along with the items which could be decoded if a client with lenient decoding enabled fails to decode some items in a list response
+type DecodeErrors []*DecodeError
+
+func (e DecodeErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%d items could not be decoded; first error: %v", len(e), e[0])
+}
+
+// decodeObject decodes a JSON response body into v. An empty body leaves v untouched.
+func decodeObject(r io.Reader, v interface{}) error {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return nil
+	}
+	err = json.Unmarshal(raw, v)
+	if err != nil {
+		return &DecodeError{Index: -1, Raw: raw, Err: err}
+	}
+	return nil
+}
+
+// decodeList decodes a JSON array item by item, calling decode with the raw JSON of every item.
+// If decode fails, decodeList returns a *DecodeError unless lenient is true, in which case it goes on and returns DecodeErrors at the end.
+func decodeList(r io.Reader, lenient bool, decode func(raw json.RawMessage) error) error {
+	dec := json.NewDecoder(r)
+
+	// read open bracket
+	t, err := dec.Token()
+	if err != nil {
+		return &DecodeError{Index: -1, Err: err}
+	}
+	if t != json.Delim('[') {
+		return &DecodeError{Index: -1, Err: errors.New("response is not a list")}
+	}
+
+	var errs DecodeErrors
+	for i := 0; dec.More(); i++ {
+		var raw json.RawMessage
+		err = dec.Decode(&raw)
+		if err != nil {
+			// the rest of the response cannot be read after a syntax error
+			return &DecodeError{Index: i, Err: err}
+		}
+		err = decode(raw)
+		if err != nil {
+			de := &DecodeError{Index: i, Raw: raw, Err: err}
+			if !lenient {
+				return de
+			}
+			errs = append(errs, de)
+		}
+	}
+
+	// read close bracket
+	_, err = dec.Token()
+	if err != nil {
+		return &DecodeError{Index: -1, Err: err}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package soracom
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newDecodeTestServer(t *testing.T, body string) string {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(ts.Close)
+	return ts.URL
+}
+
+func TestStrictDecoding(t *testing.T) {
+	endpoint := newDecodeTestServer(t, `[{"imsi":"001010000000001"},{"imsi":1},{"imsi":"001010000000003"}]`)
+	ac := NewAPIClient(&APIClientOptions{Endpoint: endpoint})
+	ac.SetAuthInfo("api-key", "token", "OP0000000000")
+
+	subs, _, err := ac.ListSubscribers(nil)
+	var de *DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected a DecodeError, got %v", err)
+	}
+	if de.Index != 1 || string(de.Raw) != `{"imsi":1}` || subs != nil {
+		t.Fatalf("unexpected DecodeError: %+v, %v", de, subs)
+	}
+
+	_, err = ac.GetSubscriber("001010000000001")
+	if !errors.As(err, &de) || de.Index != -1 {
+		t.Fatalf("expected a DecodeError for a non-list response, got %v", err)
+	}
+}
+
+func TestLenientDecoding(t *testing.T) {
+	endpoint := newDecodeTestServer(t, `[{"imsi":"001010000000001"},{"imsi":1},{"imsi":"001010000000003"},{"speedClass":[]}]`)
+	ac := NewAPIClient(&APIClientOptions{Endpoint: endpoint, LenientDecoding: true})
+	ac.SetAuthInfo("api-key", "token", "OP0000000000")
+
+	subs, _, err := ac.ListSubscribers(nil)
+	var errs DecodeErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected DecodeErrors, got %v", err)
+	}
+	if len(subs) != 2 || subs[1].IMSI != "001010000000003" {
+		t.Fatalf("decodable subscribers were not returned: %+v", subs)
+	}
+	if len(errs) != 2 || errs[0].Index != 1 || errs[1].Index != 3 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	it := ac.IterateSubscribers(context.Background(), nil)
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if it.Err() != nil || n != 2 || len(it.DecodeErrors()) != 2 {
+		t.Fatalf("iterator should skip undecodable items: %d, %v, %v", n, it.Err(), it.DecodeErrors())
+	}
+}
+
+func TestDecodeSyntaxError(t *testing.T) {
+	endpoint := newDecodeTestServer(t, `[{"groupId":"group-1"},{"groupId":`)
+	ac := NewAPIClient(&APIClientOptions{Endpoint: endpoint, LenientDecoding: true})
+	ac.SetAuthInfo("api-key", "token", "OP0000000000")
+
+	_, _, err := ac.ListGroups(nil)
+	var de *DecodeError
+	if !errors.As(err, &de) || de.Index != 1 {
+		t.Fatalf("expected a DecodeError for item 1, got %v", err)
+	}
+}
+
+func TestDecodeEmptyBody(t *testing.T) {
+	endpoint := newDecodeTestServer(t, ``)
+	ac := NewAPIClient(&APIClientOptions{Endpoint: endpoint})
+	ac.SetAuthInfo("api-key", "token", "OP0000000000")
+
+	g, err := ac.DeleteGroupConfiguration("group-1", "SoracomAir", "name")
+	if err != nil || g == nil {
+		t.Fatalf("empty body should be decoded as an empty group: %+v, %v", g, err)
+	}
+}
+
+func TestStrictDecodingSingleObject(t *testing.T) {
+	endpoint := newDecodeTestServer(t, `{"operatorId":1,"token":1}`)
+	ac := NewAPIClient(&APIClientOptions{Endpoint: endpoint, LenientDecoding: true})
+	ac.SetAuthInfo("api-key", "token", "OP0000000000")
+
+	var de *DecodeError
+	if _, err := ac.GetOperator("OP0000000000"); !errors.As(err, &de) || de.Index != -1 {
+		t.Fatalf("expected a DecodeError for an operator, got %v", err)
+	}
+	if _, err := ac.GenerateAPIToken(60); !errors.As(err, &de) {
+		t.Fatalf("expected a DecodeError for an API token, got %v", err)
+	}
+	if _, err := ac.GetSupportToken(); !errors.As(err, &de) {
+		t.Fatalf("expected a DecodeError for a support token, got %v", err)
+	}
+	if err := ac.Auth("user@example.com", "password"); !errors.As(err, &de) {
+		t.Fatalf("expected a DecodeError for an auth response, got %v", err)
+	}
+}
+
+func TestDecodeBeamStats(t *testing.T) {
+	body := `[{"date":"20230101","unixtime":1672531200,"beamStatsMap":{"inHttp":{"count":1}}},{"unixtime":"x"}]`
+	for _, lenient := range []bool{false, true} {
+		ac := NewAPIClient(&APIClientOptions{Endpoint: newDecodeTestServer(t, body), LenientDecoding: lenient})
+		ac.SetAuthInfo("api-key", "token", "OP0000000000")
+
+		stats, err := ac.GetBeamStats("001010000000001", time.Unix(0, 0), time.Unix(1, 0), StatsPeriodDay)
+		if lenient {
+			var errs DecodeErrors
+			if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Index != 1 {
+				t.Fatalf("expected DecodeErrors for item 1, got %v", err)
+			}
+			if len(stats) != 1 || stats[0].Traffic[BeamTypeInHTTP].Count != 1 {
+				t.Fatalf("decodable stats were not returned: %+v", stats)
+			}
+			continue
+		}
+		var de *DecodeError
+		if !errors.As(err, &de) || de.Index != 1 || stats != nil {
+			t.Fatalf("expected a DecodeError for item 1, got %v, %+v", err, stats)
+		}
+	}
+
+	ac := NewAPIClient(&APIClientOptions{Endpoint: newDecodeTestServer(t, `{"url":1}`)})
+	ac.SetAuthInfo("api-key", "token", "OP0000000000")
+	var de *DecodeError
+	if _, err := ac.ExportBeamStats(time.Unix(0, 0), time.Unix(1, 0), StatsPeriodDay); !errors.As(err, &de) {
+		t.Fatalf("expected a DecodeError for an export, got %v", err)
+	}
+	if _, err := ac.ExportAirStats(time.Unix(0, 0), time.Unix(1, 0), StatsPeriodDay); !errors.As(err, &de) {
+		t.Fatalf("expected a DecodeError for an export, got %v", err)
+	}
+}
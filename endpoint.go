@@ -0,0 +1,66 @@
+package soracom
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// CoverageType represents one of coverage types of SORACOM
+type CoverageType string
+
+const (
+	// CoverageTypeJapan is Japan coverage
+	CoverageTypeJapan CoverageType = "jp"
+
+	// CoverageTypeGlobal is Global coverage
+	CoverageTypeGlobal CoverageType = "g"
+)
+
+func (c CoverageType) String() string {
+	return string(c)
+}
+
+// ErrNotSandbox is returned when a sandbox-only API is called on a client which is not connected to a sandbox
+var ErrNotSandbox = errors.New("sandbox API cannot be called against a non-sandbox endpoint")
+
+const sandboxPathPrefix = "/v1/sandbox/"
+
+// APIEndpoint returns the URL of SORACOM API endpoint for the specified coverage type.
+// The endpoint of the API sandbox is returned if sandbox is true.
+func APIEndpoint(coverageType CoverageType, sandbox bool) string {
+	host := "api.soracom.io"
+	if sandbox {
+		host = "api-sandbox.soracom.io"
+	}
+	if coverageType == CoverageTypeGlobal {
+		host = "g." + host
+	}
+	return "https://" + host
+}
+
+// productionHosts are hosts of the production API. Clients connected to them are never considered to be connected to a sandbox.
+var productionHosts = []string{"api.soracom.io", "g.api.soracom.io"}
+
+// isProductionEndpoint reports whether endpoint points to the production API
+func isProductionEndpoint(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return false
+	}
+	for _, h := range productionHosts {
+		if strings.EqualFold(u.Hostname(), h) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSandboxEndpoint reports whether endpoint points to one of the API sandboxes
+func isSandboxEndpoint(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(u.Hostname(), "api-sandbox.") || strings.HasPrefix(u.Hostname(), "g.api-sandbox.")
+}
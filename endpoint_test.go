@@ -0,0 +1,107 @@
+package soracom
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIEndpoint(t *testing.T) {
+	testData := []struct {
+		coverageType CoverageType
+		sandbox      bool
+		expected     string
+	}{
+		{"", false, "https://api.soracom.io"},
+		{CoverageTypeJapan, false, "https://api.soracom.io"},
+		{CoverageTypeGlobal, false, "https://g.api.soracom.io"},
+		{CoverageTypeJapan, true, "https://api-sandbox.soracom.io"},
+		{CoverageTypeGlobal, true, "https://g.api-sandbox.soracom.io"},
+	}
+	for _, data := range testData {
+		if e := APIEndpoint(data.coverageType, data.sandbox); e != data.expected {
+			t.Errorf("APIEndpoint(%q, %v): expected %s, got %s", data.coverageType, data.sandbox, data.expected, e)
+		}
+	}
+}
+
+func TestNewAPIClientEndpoint(t *testing.T) {
+	testData := []struct {
+		options  *APIClientOptions
+		endpoint string
+		sandbox  bool
+	}{
+		{nil, "https://api.soracom.io", false},
+		{&APIClientOptions{CoverageType: CoverageTypeGlobal}, "https://g.api.soracom.io", false},
+		{&APIClientOptions{CoverageType: CoverageTypeGlobal, Sandbox: true}, "https://g.api-sandbox.soracom.io", true},
+		{&APIClientOptions{Endpoint: "https://api-sandbox.soracom.io"}, "https://api-sandbox.soracom.io", true},
+		{&APIClientOptions{Endpoint: "http://localhost:8080"}, "http://localhost:8080", false},
+		{&APIClientOptions{Endpoint: "http://localhost:8080", Sandbox: true}, "http://localhost:8080", true},
+		{&APIClientOptions{Endpoint: "https://api.soracom.io", Sandbox: true}, "https://api.soracom.io", false},
+		{&APIClientOptions{Endpoint: "https://g.api.soracom.io/", Sandbox: true}, "https://g.api.soracom.io/", false},
+	}
+	for _, data := range testData {
+		ac := NewAPIClient(data.options)
+		if ac.endpoint != data.endpoint || ac.IsSandbox() != data.sandbox {
+			t.Errorf("%+v: expected (%s, %v), got (%s, %v)", data.options, data.endpoint, data.sandbox, ac.endpoint, ac.IsSandbox())
+		}
+	}
+}
+
+func TestSandboxAPIsRefuseNonSandboxEndpoint(t *testing.T) {
+	called := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	ac := NewAPIClient(&APIClientOptions{Endpoint: ts.URL})
+	_, err := ac.InitOperatorForSandbox("test@example.com", "password", "keyId-xxx", "secret-xxx", true, nil)
+	if !errors.Is(err, ErrNotSandbox) {
+		t.Fatalf("InitOperatorForSandbox(): expected ErrNotSandbox, got %v", err)
+	}
+	_, err = ac.CreateSubscriber()
+	if !errors.Is(err, ErrNotSandbox) {
+		t.Fatalf("CreateSubscriber(): expected ErrNotSandbox, got %v", err)
+	}
+	err = ac.InsertAirStats("001010000000001", AirStats{})
+	if !errors.Is(err, ErrNotSandbox) {
+		t.Fatalf("InsertAirStats(): expected ErrNotSandbox, got %v", err)
+	}
+	_, err = ac.CreateCoupon(nil)
+	if !errors.Is(err, ErrNotSandbox) {
+		t.Fatalf("CreateCoupon(): expected ErrNotSandbox, got %v", err)
+	}
+	err = ac.DeleteSandboxOperator()
+	if !errors.Is(err, ErrNotSandbox) {
+		t.Fatalf("DeleteSandboxOperator(): expected ErrNotSandbox, got %v", err)
+	}
+	if called {
+		t.Fatal("sandbox API should not have been called")
+	}
+
+	ac = NewAPIClient(&APIClientOptions{Endpoint: ts.URL, Sandbox: true})
+	_, err = ac.CreateSubscriber()
+	if err != nil {
+		t.Fatalf("CreateSubscriber() failed: %v", err)
+	}
+}
+
+func TestNewAPIClientWithCredentialsCoverageType(t *testing.T) {
+	p := &StaticCredentialsProvider{Credentials: AuthCredentials{
+		AuthKeyID:    "keyId-xxx",
+		AuthKey:      "secret-xxx",
+		CoverageType: CoverageTypeGlobal,
+		Sandbox:      true,
+	}}
+	ac, err := NewAPIClientWithCredentials(p, nil)
+	if err != nil {
+		t.Fatalf("NewAPIClientWithCredentials() failed: %v", err)
+	}
+	if ac.endpoint != "https://g.api-sandbox.soracom.io" || !ac.IsSandbox() {
+		t.Fatalf("unexpected endpoint: %s", ac.endpoint)
+	}
+}
@@ -9,26 +9,12 @@ import (
 )
 
 func main() {
-	email := os.Getenv("SORACOM_EMAIL")
-	password := os.Getenv("SORACOM_PASSWORD")
-
-	if email == "" {
-		fmt.Println("SORACOM_EMAIL env var is required")
-		os.Exit(1)
-		return
-	}
-
-	if password == "" {
-		fmt.Println("SORACOM_PASSWORD env var is required")
-		os.Exit(1)
-		return
-	}
-
-	ac := soracom.NewAPIClient(nil)
-
-	err := ac.Auth(email, password)
+	// Credentials are read from SORACOM_AUTH_KEY_ID/SORACOM_AUTH_KEY or SORACOM_EMAIL/SORACOM_PASSWORD env vars,
+	// or from the soracom-cli profile specified by SORACOM_PROFILE env var.
+	ac, err := soracom.NewAPIClientWithCredentials(soracom.NewDefaultCredentialsProvider(nil), nil)
 	if err != nil {
-		fmt.Printf("auth err: %v\n", err.Error())
+		fmt.Printf("credentials err: %v\n", err.Error())
+		os.Exit(1)
 		return
 	}
 
@@ -0,0 +1,141 @@
+package soracom
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Providers and services of SORACOM Funnel destinations
+const (
+	FunnelProviderAWS     = "aws"
+	FunnelProviderAzure   = "azure"
+	FunnelProviderGoogle  = "google"
+	FunnelProviderWebhook = "webhook"
+
+	FunnelServiceKinesis   = "kinesis"
+	FunnelServiceFirehose  = "firehose"
+	FunnelServiceAWSIoT    = "aws-iot"
+	FunnelServiceEventHubs = "eventhubs"
+	FunnelServicePubSub    = "pubsub"
+	FunnelServiceWebhook   = "webhook"
+)
+
+// Types of credential sets created with CreateCredentialWithName
+const (
+	CredentialTypeAWS                  = "aws-credentials"
+	CredentialTypeAWSIAMRole           = "aws-iam-role-credentials"
+	CredentialTypeAzure                = "azure-credentials"
+	CredentialTypeGoogleServiceAccount = "google-service-account-json"
+	CredentialTypeAPIToken             = "api-token-credentials"
+	CredentialTypeUsernamePassword     = "username-password-credentials"
+	CredentialTypeX509                 = "x509"
+	CredentialTypePreSharedKey         = "psk"
+)
+
+var (
+	// ErrCredentialTypeMismatch is returned if a credential set cannot be used for a SORACOM Funnel destination
+	ErrCredentialTypeMismatch = errors.New("credential type does not match the destination")
+
+	// ErrCredentialRequired is returned if a SORACOM Funnel destination needs a credential set but none is given
+	ErrCredentialRequired = errors.New("credential is required for the destination")
+
+	// ErrFunnelConfigRequired is returned by UpdateFunnelConfig if no configuration is given
+	ErrFunnelConfigRequired = errors.New("funnel configuration is required")
+)
+
+// funnelCredentialTypes are the credential types each provider accepts
+var funnelCredentialTypes = map[string][]string{
+	FunnelProviderAWS:     {CredentialTypeAWS, CredentialTypeAWSIAMRole},
+	FunnelProviderAzure:   {CredentialTypeAzure},
+	FunnelProviderGoogle:  {CredentialTypeGoogleServiceAccount},
+	FunnelProviderWebhook: {CredentialTypeAPIToken, CredentialTypeUsernamePassword},
+}
+
+// funnelCredentialOptional lists providers which can be used without credentials
+var funnelCredentialOptional = map[string]bool{
+	FunnelProviderWebhook: true,
+}
+
+// FunnelAWSKinesis returns a destination sending data to an Amazon Kinesis Data Stream
+func FunnelAWSKinesis(region, streamName string) FunnelDestinationConfig {
+	return FunnelDestinationConfig{
+		Provider:    FunnelProviderAWS,
+		Service:     FunnelServiceKinesis,
+		ResourceUrl: "https://kinesis." + region + ".amazonaws.com/" + url.PathEscape(streamName),
+	}
+}
+
+// FunnelAWSFirehose returns a destination sending data to an Amazon Kinesis Data Firehose delivery stream
+func FunnelAWSFirehose(region, deliveryStreamName string) FunnelDestinationConfig {
+	return FunnelDestinationConfig{
+		Provider:    FunnelProviderAWS,
+		Service:     FunnelServiceFirehose,
+		ResourceUrl: "https://firehose." + region + ".amazonaws.com/" + url.PathEscape(deliveryStreamName),
+	}
+}
+
+// FunnelAWSIoT returns a destination publishing data to a topic of AWS IoT, e.g. endpoint "xxx-ats.iot.ap-northeast-1.amazonaws.com".
+// Each level of the topic is escaped, keeping the "/" between levels as the path of the resource URL.
+func FunnelAWSIoT(endpoint, topic string) FunnelDestinationConfig {
+	levels := strings.Split(topic, "/")
+	for i, level := range levels {
+		levels[i] = url.PathEscape(level)
+	}
+	return FunnelDestinationConfig{
+		Provider:    FunnelProviderAWS,
+		Service:     FunnelServiceAWSIoT,
+		ResourceUrl: "https://" + endpoint + "/" + strings.Join(levels, "/"),
+	}
+}
+
+// FunnelAzureEventHubs returns a destination sending data to an event hub in an Azure Event Hubs namespace
+func FunnelAzureEventHubs(namespace, eventHub string) FunnelDestinationConfig {
+	return FunnelDestinationConfig{
+		Provider:    FunnelProviderAzure,
+		Service:     FunnelServiceEventHubs,
+		ResourceUrl: "https://" + namespace + ".servicebus.windows.net/" + url.PathEscape(eventHub) + "/messages",
+	}
+}
+
+// FunnelGooglePubSub returns a destination publishing data to a topic of Google Cloud Pub/Sub
+func FunnelGooglePubSub(project, topic string) FunnelDestinationConfig {
+	return FunnelDestinationConfig{
+		Provider:    FunnelProviderGoogle,
+		Service:     FunnelServicePubSub,
+		ResourceUrl: "https://pubsub.googleapis.com/v1/projects/" + url.PathEscape(project) + "/topics/" + url.PathEscape(topic),
+	}
+}
+
+// FunnelWebhook returns a destination posting data to a URL
+func FunnelWebhook(endpoint string) FunnelDestinationConfig {
+	return FunnelDestinationConfig{
+		Provider:    FunnelProviderWebhook,
+		Service:     FunnelServiceWebhook,
+		ResourceUrl: endpoint,
+	}
+}
+
+// ValidateFunnelCredential checks that the credential set can be used for the destination.
+// The error matches ErrCredentialTypeMismatch if it cannot, or ErrCredentialRequired if credential is nil and the destination needs one.
+// Destinations of providers unknown to this SDK accept any credential set.
+func ValidateFunnelCredential(destination FunnelDestinationConfig, credential *CreatedCredential) error {
+	types, ok := funnelCredentialTypes[destination.Provider]
+	if !ok {
+		return nil
+	}
+	if credential == nil {
+		if funnelCredentialOptional[destination.Provider] {
+			return nil
+		}
+		return fmt.Errorf("%w: %s %s", ErrCredentialRequired, destination.Provider, destination.Service)
+	}
+	for _, t := range types {
+		if credential.Type == t {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: credential %s of type %q cannot be used for %s %s, one of %q is needed",
+		ErrCredentialTypeMismatch, credential.CredentialID, credential.Type, destination.Provider, destination.Service, types)
+}
@@ -0,0 +1,113 @@
+package soracom
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFunnelDestinations(t *testing.T) {
+	var testData = []struct {
+		Name        string
+		Destination FunnelDestinationConfig
+		Service     string
+		ResourceURL string
+	}{
+		{"kinesis", FunnelAWSKinesis("ap-northeast-1", "stream"), "kinesis", "https://kinesis.ap-northeast-1.amazonaws.com/stream"},
+		{"firehose", FunnelAWSFirehose("us-west-2", "delivery"), "firehose", "https://firehose.us-west-2.amazonaws.com/delivery"},
+		{"aws iot", FunnelAWSIoT("xxx-ats.iot.ap-northeast-1.amazonaws.com", "devices/data"), "aws-iot", "https://xxx-ats.iot.ap-northeast-1.amazonaws.com/devices/data"},
+		{"aws iot with special characters", FunnelAWSIoT("xxx-ats.iot.ap-northeast-1.amazonaws.com", "devices/my data?/#"), "aws-iot", "https://xxx-ats.iot.ap-northeast-1.amazonaws.com/devices/my%20data%3F/%23"},
+		{"event hubs", FunnelAzureEventHubs("ns", "hub"), "eventhubs", "https://ns.servicebus.windows.net/hub/messages"},
+		{"pubsub", FunnelGooglePubSub("project", "topic"), "pubsub", "https://pubsub.googleapis.com/v1/projects/project/topics/topic"},
+		{"webhook", FunnelWebhook("https://example.com/hook"), "webhook", "https://example.com/hook"},
+	}
+
+	for _, data := range testData {
+		data := data
+		t.Run(data.Name, func(t *testing.T) {
+			if data.Destination.Service != data.Service || data.Destination.ResourceUrl != data.ResourceURL {
+				t.Fatalf("unexpected destination: %+v", data.Destination)
+			}
+		})
+	}
+}
+
+func TestValidateFunnelCredential(t *testing.T) {
+	aws := &CreatedCredential{CredentialID: "aws", Type: CredentialTypeAWS}
+	role := &CreatedCredential{CredentialID: "role", Type: CredentialTypeAWSIAMRole}
+	azure := &CreatedCredential{CredentialID: "azure", Type: CredentialTypeAzure}
+	google := &CreatedCredential{CredentialID: "google", Type: CredentialTypeGoogleServiceAccount}
+	token := &CreatedCredential{CredentialID: "token", Type: CredentialTypeAPIToken}
+
+	var testData = []struct {
+		Name        string
+		Destination FunnelDestinationConfig
+		Credential  *CreatedCredential
+		Err         error
+	}{
+		{"kinesis with aws", FunnelAWSKinesis("ap-northeast-1", "s"), aws, nil},
+		{"firehose with iam role", FunnelAWSFirehose("ap-northeast-1", "s"), role, nil},
+		{"aws iot with azure", FunnelAWSIoT("e", "t"), azure, ErrCredentialTypeMismatch},
+		{"event hubs with azure", FunnelAzureEventHubs("ns", "hub"), azure, nil},
+		{"event hubs with aws", FunnelAzureEventHubs("ns", "hub"), aws, ErrCredentialTypeMismatch},
+		{"pubsub with google", FunnelGooglePubSub("p", "t"), google, nil},
+		{"pubsub without credential", FunnelGooglePubSub("p", "t"), nil, ErrCredentialRequired},
+		{"webhook with token", FunnelWebhook("https://example.com"), token, nil},
+		{"webhook without credential", FunnelWebhook("https://example.com"), nil, nil},
+		{"webhook with google", FunnelWebhook("https://example.com"), google, ErrCredentialTypeMismatch},
+		{"unknown provider", FunnelDestinationConfig{Provider: "other"}, google, nil},
+	}
+
+	for _, data := range testData {
+		data := data
+		t.Run(data.Name, func(t *testing.T) {
+			err := ValidateFunnelCredential(data.Destination, data.Credential)
+			if !errors.Is(err, data.Err) || (data.Err == nil && err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestUpdateFunnelConfig(t *testing.T) {
+	ac, s := newFakeGroupServer(t, &Group{GroupID: "group-1"})
+
+	cred := &CreatedCredential{CredentialID: "my-aws", Type: CredentialTypeAWS}
+	dest := FunnelAWSKinesis("ap-northeast-1", "stream")
+	dest.Extra = map[string]interface{}{"randomizePartitionKey": true}
+	g, err := ac.UpdateFunnelConfig("group-1", &FunnelConfig{Enabled: true, Destination: dest, ContentType: "application/json"}, cred)
+	if err != nil {
+		t.Fatalf("UpdateFunnelConfig() failed: %v", err)
+	}
+	funnel, err := g.FunnelConfig()
+	if err != nil {
+		t.Fatalf("FunnelConfig() failed: %v", err)
+	}
+	if !funnel.Enabled || funnel.CredentialsID != "my-aws" || funnel.Destination.ResourceUrl != dest.ResourceUrl || funnel.Destination.Extra["randomizePartitionKey"] != true {
+		t.Fatalf("unexpected funnel config: %+v", funnel)
+	}
+
+	_, err = ac.UpdateFunnelConfig("group-1", &FunnelConfig{Enabled: true, Destination: FunnelGooglePubSub("p", "t")}, cred)
+	if !errors.Is(err, ErrCredentialTypeMismatch) {
+		t.Fatalf("expected ErrCredentialTypeMismatch: %v", err)
+	}
+	_, err = ac.UpdateFunnelConfig("group-1", &FunnelConfig{Enabled: true, Destination: FunnelGooglePubSub("p", "t")}, nil)
+	if !errors.Is(err, ErrCredentialRequired) {
+		t.Fatalf("expected ErrCredentialRequired: %v", err)
+	}
+	_, err = ac.UpdateFunnelConfig("group-1", nil, cred)
+	if !errors.Is(err, ErrFunnelConfigRequired) {
+		t.Fatalf("expected ErrFunnelConfigRequired: %v", err)
+	}
+	if len(s.calls) != 1 {
+		t.Fatalf("invalid configurations should not be sent: %v", s.calls)
+	}
+
+	g, err = ac.UpdateFunnelConfig("group-1", &FunnelConfig{Enabled: false, Destination: FunnelGooglePubSub("p", "t"), CredentialsID: "existing"}, nil)
+	if err != nil {
+		t.Fatalf("UpdateFunnelConfig() with an existing credential ID failed: %v", err)
+	}
+	funnel, _ = g.FunnelConfig()
+	if funnel.Enabled || funnel.CredentialsID != "existing" || funnel.Destination.Service != FunnelServicePubSub {
+		t.Fatalf("unexpected funnel config: %+v", funnel)
+	}
+}
@@ -0,0 +1,196 @@
+package soracom
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// GroupRewriter rewrites a string value of a group while it is cloned.
+// namespace is empty for tags, and key is the tag name or the configuration key in the namespace the value is found under.
+type GroupRewriter func(namespace ConfigNamespace, key, value string) (string, error)
+
+// ReplaceStrings returns a GroupRewriter replacing old strings with new ones as strings.NewReplacer does
+func ReplaceStrings(oldnew ...string) GroupRewriter {
+	r := strings.NewReplacer(oldnew...)
+	return func(namespace ConfigNamespace, key, value string) (string, error) {
+		return r.Replace(value), nil
+	}
+}
+
+// ExecuteTemplates returns a GroupRewriter executing values containing "{{" as text/template templates with data,
+// e.g. "https://{{.Customer}}.example.com" in a Beam destination of a template group
+func ExecuteTemplates(data interface{}) GroupRewriter {
+	return func(namespace ConfigNamespace, key, value string) (string, error) {
+		if !strings.Contains(value, "{{") {
+			return value, nil
+		}
+		tmpl, err := template.New(string(namespace) + " " + key).Option("missingkey=error").Parse(value)
+		if err != nil {
+			return "", err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+}
+
+// CloneGroupOptions holds options for APIClient.CloneGroup()
+type CloneGroupOptions struct {
+	// Destination is the client the group is created with, e.g. one authenticated as another operator. Defaults to the client cloning the group.
+	// When the destination client is authenticated as another operator, credentialsId keys, such as that of SORACOM Funnel, refer to credential sets of the source operator,
+	// so they are left out unless Rewrite changes them to IDs of credential sets of the destination operator.
+	Destination *APIClient
+
+	// Tags are set on the new group in addition to the tags of the source group, e.g. a new name
+	Tags Tags
+
+	// Rewrite rewrites every string value in tags and configurations if not nil
+	Rewrite GroupRewriter
+}
+
+// rewriteValue applies rewrite to every string in v, which is a value decoded as generic JSON
+func rewriteValue(v interface{}, namespace ConfigNamespace, key string, rewrite GroupRewriter) (interface{}, error) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			r, err := rewriteValue(e, namespace, key, rewrite)
+			if err != nil {
+				return nil, err
+			}
+			t[k] = r
+		}
+	case []interface{}:
+		for i, e := range t {
+			r, err := rewriteValue(e, namespace, key, rewrite)
+			if err != nil {
+				return nil, err
+			}
+			t[i] = r
+		}
+	case string:
+		return rewrite(namespace, key, t)
+	}
+	return v, nil
+}
+
+// CloneGroup creates a copy of a group with its tags and configurations, leaving out identifiers and timestamps of the source group.
+// If a configuration cannot be set, the new group is deleted and the error is returned.
+func (ac *APIClient) CloneGroup(groupID string, options *CloneGroupOptions) (*Group, error) {
+	return ac.CloneGroupWithContext(context.Background(), groupID, options)
+}
+
+// CloneGroupWithContext is the context-aware version of CloneGroup.
+func (ac *APIClient) CloneGroupWithContext(ctx context.Context, groupID string, options *CloneGroupOptions) (*Group, error) {
+	dest := ac
+	var extraTags Tags
+	var rewrite GroupRewriter
+	if options != nil {
+		if options.Destination != nil {
+			dest = options.Destination
+		}
+		extraTags = options.Tags
+		rewrite = options.Rewrite
+	}
+
+	src, err := ac.GetGroupWithContext(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	// credentials are registered per operator, so another client for the same operator can still use them
+	crossOperator := false
+	if dest != ac {
+		srcOperatorID, err := ac.currentOperatorID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		destOperatorID, err := dest.currentOperatorID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		crossOperator = srcOperatorID != destOperatorID
+	}
+
+	tags := Tags{}
+	for k, v := range src.Tags {
+		tags[k] = v
+	}
+	for k, v := range extraTags {
+		tags[k] = v
+	}
+	if rewrite != nil {
+		for k, v := range tags {
+			if tags[k], err = rewrite("", k, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// configurations are prepared before creating the group so that a failing rewrite leaves nothing behind
+	namespaces := make([]string, 0, len(src.Configuration))
+	configs := map[string][]GroupConfig{}
+	for ns, value := range src.Configuration {
+		var m map[string]interface{}
+		if err := decodeConfigValue(value, &m); err != nil {
+			return nil, err
+		}
+		if len(m) == 0 {
+			continue
+		}
+		for _, c := range groupConfigs(m) {
+			original := c.Value
+			if rewrite != nil {
+				if c.Value, err = rewriteValue(c.Value, ns, c.Key, rewrite); err != nil {
+					return nil, err
+				}
+			}
+			if c.Key == "credentialsId" && crossOperator && reflect.DeepEqual(c.Value, original) {
+				continue
+			}
+			configs[string(ns)] = append(configs[string(ns)], c)
+		}
+		if len(configs[string(ns)]) > 0 {
+			namespaces = append(namespaces, string(ns))
+		}
+	}
+	sort.Strings(namespaces)
+
+	g, err := dest.CreateGroupWithContext(ctx, tags)
+	if err != nil {
+		return nil, err
+	}
+	for _, ns := range namespaces {
+		updated, err := dest.UpdateGroupConfigurationsWithContext(ctx, g.GroupID, ns, configs[ns])
+		if err != nil {
+			_ = dest.DeleteGroupWithContext(ctx, g.GroupID)
+			return nil, err
+		}
+		g = updated
+	}
+	return g, nil
+}
+
+// CloneGroups clones groups in order as CloneGroup does and returns a mapping of source group IDs to IDs of new groups.
+// If cloning a group fails, the mapping holds the groups cloned so far.
+func (ac *APIClient) CloneGroups(groupIDs []string, options *CloneGroupOptions) (map[string]string, error) {
+	return ac.CloneGroupsWithContext(context.Background(), groupIDs, options)
+}
+
+// CloneGroupsWithContext is the context-aware version of CloneGroups.
+func (ac *APIClient) CloneGroupsWithContext(ctx context.Context, groupIDs []string, options *CloneGroupOptions) (map[string]string, error) {
+	ids := make(map[string]string, len(groupIDs))
+	for _, id := range groupIDs {
+		g, err := ac.CloneGroupWithContext(ctx, id, options)
+		if err != nil {
+			return ids, err
+		}
+		ids[id] = g.GroupID
+	}
+	return ids, nil
+}
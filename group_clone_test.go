@@ -0,0 +1,146 @@
+package soracom
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCloneGroups(t *testing.T) {
+	src, _ := newFakeGroupServer(t,
+		&Group{
+			GroupID:    "template-1",
+			OperatorID: "OP0000000001",
+			Tags:       Tags{"name": "template", "kind": "gateway"},
+			Configuration: map[ConfigNamespace]interface{}{
+				ConfigNamespaceAir: map[string]interface{}{"useCustomDns": false, "userdata": `{"customer":"{{.Customer}}"}`},
+				ConfigNamespaceBeam: map[string]interface{}{
+					BeamEntryPointTCP: map[string]interface{}{"name": "tcp", "destination": "tcps://{{.Customer}}.example.com:1234", "enabled": true},
+				},
+			},
+		},
+		&Group{GroupID: "template-2", Tags: Tags{"name": "empty"}},
+	)
+	dest, d := newFakeGroupServer(t)
+
+	ids, err := src.CloneGroups([]string{"template-1", "template-2"}, &CloneGroupOptions{
+		Destination: dest,
+		Tags:        Tags{"customer": "{{.Customer}}"},
+		Rewrite:     ExecuteTemplates(map[string]string{"Customer": "acme"}),
+	})
+	if err != nil {
+		t.Fatalf("CloneGroups() failed: %v", err)
+	}
+	if len(ids) != 2 || ids["template-1"] == "" || ids["template-2"] == "" || ids["template-1"] == ids["template-2"] {
+		t.Fatalf("unexpected mapping: %v", ids)
+	}
+
+	g, err := dest.GetGroup(ids["template-1"])
+	if err != nil {
+		t.Fatalf("GetGroup() failed: %v", err)
+	}
+	if g.Tags["name"] != "template" || g.Tags["kind"] != "gateway" || g.Tags["customer"] != "acme" {
+		t.Fatalf("unexpected tags: %v", g.Tags)
+	}
+	air, err := g.AirConfig()
+	if err != nil || air.UserData != `{"customer":"acme"}` {
+		t.Fatalf("unexpected air config: %+v, %v", air, err)
+	}
+	tcp, err := g.BeamTCPConfig()
+	if err != nil || tcp.Destination != "tcps://acme.example.com:1234" || !tcp.Enabled {
+		t.Fatalf("unexpected beam config: %+v, %v", tcp, err)
+	}
+	if g.OperatorID != "OP0000000000" {
+		t.Fatalf("group should be created for the destination operator: %s", g.OperatorID)
+	}
+	if _, err := src.GetGroup(ids["template-1"]); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("group should not be created with the source client: %v", err)
+	}
+
+	g, err = src.CloneGroup("template-1", &CloneGroupOptions{Rewrite: ReplaceStrings("{{.Customer}}", "other")})
+	if err != nil {
+		t.Fatalf("CloneGroup() failed: %v", err)
+	}
+	tcp, err = g.BeamTCPConfig()
+	if err != nil || tcp.Destination != "tcps://other.example.com:1234" {
+		t.Fatalf("unexpected beam config: %+v, %v", tcp, err)
+	}
+
+	d.calls = nil
+	_, err = src.CloneGroup("template-1", &CloneGroupOptions{Destination: dest, Rewrite: ExecuteTemplates(map[string]string{})})
+	if err == nil {
+		t.Fatalf("missing template data should be an error")
+	}
+	if len(d.calls) != 0 {
+		t.Fatalf("nothing should be created if rewriting fails: %v", d.calls)
+	}
+
+	// the next groups created are group-3 and group-4
+	d.failPath = "/v1/groups/group-4/configuration/SoracomBeam"
+	ids, err = src.CloneGroups([]string{"template-2", "template-1"}, &CloneGroupOptions{Destination: dest})
+	if err == nil || len(ids) != 1 || ids["template-2"] != "group-3" {
+		t.Fatalf("CloneGroups() should fail on the second group: %v, %v", ids, err)
+	}
+	if _, ok := d.groups["group-4"]; ok {
+		t.Fatalf("group should be deleted if a configuration cannot be set")
+	}
+}
+
+func TestCloneGroupAcrossOperatorsStripsCredentials(t *testing.T) {
+	newSource := func() *Group {
+		return &Group{
+			GroupID: "template-1",
+			Tags:    Tags{"name": "template"},
+			Configuration: map[ConfigNamespace]interface{}{
+				ConfigNamespaceFunnel: map[string]interface{}{
+					"enabled":       true,
+					"credentialsId": "source-credential",
+					"destination":   map[string]interface{}{"provider": "aws", "service": "kinesis", "resourceUrl": "https://kinesis.ap-northeast-1.amazonaws.com/stream"},
+				},
+			},
+		}
+	}
+	src, _ := newFakeGroupServer(t, newSource())
+	dest, _ := newFakeGroupServer(t)
+	dest.SetAuthInfo("api-key", "token", "OP0000000001")
+
+	g, err := src.CloneGroup("template-1", &CloneGroupOptions{Destination: dest})
+	if err != nil {
+		t.Fatalf("CloneGroup() failed: %v", err)
+	}
+	funnel, err := g.FunnelConfig()
+	if err != nil {
+		t.Fatalf("FunnelConfig() failed: %v", err)
+	}
+	if funnel.CredentialsID != "" || !funnel.Enabled || funnel.Destination.Service != FunnelServiceKinesis {
+		t.Fatalf("credentialsId of the source operator should be left out: %+v", funnel)
+	}
+
+	g, err = src.CloneGroup("template-1", &CloneGroupOptions{Destination: dest, Rewrite: ReplaceStrings("source-credential", "dest-credential")})
+	if err != nil {
+		t.Fatalf("CloneGroup() failed: %v", err)
+	}
+	funnel, _ = g.FunnelConfig()
+	if funnel.CredentialsID != "dest-credential" {
+		t.Fatalf("credentialsId rewritten for the destination operator should be kept: %+v", funnel)
+	}
+
+	g, err = src.CloneGroup("template-1", nil)
+	if err != nil {
+		t.Fatalf("CloneGroup() failed: %v", err)
+	}
+	funnel, _ = g.FunnelConfig()
+	if funnel.CredentialsID != "source-credential" {
+		t.Fatalf("credentialsId should be kept when cloning within the operator: %+v", funnel)
+	}
+
+	// another client, e.g. with a different SAM user, for the same operator
+	sameOperator, _ := newFakeGroupServer(t)
+	g, err = src.CloneGroup("template-1", &CloneGroupOptions{Destination: sameOperator})
+	if err != nil {
+		t.Fatalf("CloneGroup() failed: %v", err)
+	}
+	funnel, _ = g.FunnelConfig()
+	if funnel.CredentialsID != "source-credential" {
+		t.Fatalf("credentialsId should be kept when cloning with another client for the same operator: %+v", funnel)
+	}
+}
@@ -0,0 +1,351 @@
+package soracom
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Namespaces of group configurations
+const (
+	ConfigNamespaceAir             ConfigNamespace = "SoracomAir"
+	ConfigNamespaceBeam            ConfigNamespace = "SoracomBeam"
+	ConfigNamespaceEndorse         ConfigNamespace = "SoracomEndorse"
+	ConfigNamespaceFunk            ConfigNamespace = "SoracomFunk"
+	ConfigNamespaceFunnel          ConfigNamespace = "SoracomFunnel"
+	ConfigNamespaceHarvest         ConfigNamespace = "SoracomHarvest"
+	ConfigNamespaceHarvestFiles    ConfigNamespace = "SoracomHarvestFiles"
+	ConfigNamespaceKrypton         ConfigNamespace = "SoracomKrypton"
+	ConfigNamespaceOrbit           ConfigNamespace = "SoracomOrbit"
+	ConfigNamespaceUnifiedEndpoint ConfigNamespace = "UnifiedEndpoint"
+)
+
+// jsonFieldNames returns the JSON keys of the fields of the struct v points to
+func jsonFieldNames(v interface{}) map[string]bool {
+	names := map[string]bool{}
+	t := reflect.TypeOf(v).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names[name] = true
+	}
+	return names
+}
+
+// unmarshalWithExtra decodes b into the struct v points to and stores values of keys unknown to the struct in extra.
+// v must not implement json.Unmarshaler, so callers pass a pointer to a type defined on their own type.
+func unmarshalWithExtra(b []byte, v interface{}, extra *map[string]interface{}) error {
+	if err := json.Unmarshal(b, v); err != nil {
+		return err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	for name := range jsonFieldNames(v) {
+		delete(m, name)
+	}
+	if len(m) == 0 {
+		m = nil
+	}
+	*extra = m
+	return nil
+}
+
+// marshalWithExtra encodes the struct v points to with values in extra. Fields of the struct take precedence over extra.
+func marshalWithExtra(v interface{}, extra map[string]interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return b, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	for k, x := range extra {
+		if _, ok := m[k]; !ok {
+			m[k] = x
+		}
+	}
+	return json.Marshal(m)
+}
+
+// groupConfigs converts a configuration into key-value pairs for UpdateGroupConfigurations, sorted by key
+func groupConfigs(v interface{}) []GroupConfig {
+	var m map[string]interface{}
+	if err := decodeConfigValue(v, &m); err != nil {
+		return nil
+	}
+	configs := make([]GroupConfig, 0, len(m))
+	for k, x := range m {
+		configs = append(configs, GroupConfig{Key: k, Value: x})
+	}
+	sort.Slice(configs, func(i, j int) bool { return configs[i].Key < configs[j].Key })
+	return configs
+}
+
+// namespaceConfig decodes the configuration in the namespace into v. The error matches ErrNotFound if the namespace is not configured.
+func (g *Group) namespaceConfig(namespace ConfigNamespace, v interface{}) error {
+	value, ok := g.Configuration[namespace]
+	if !ok || value == nil {
+		return fmt.Errorf("%w: %s is not configured for group %s", ErrNotFound, namespace, g.GroupID)
+	}
+	if err := decodeConfigValue(value, v); err != nil {
+		return fmt.Errorf("failed to decode %s configuration of group %s: %w", namespace, g.GroupID, err)
+	}
+	return nil
+}
+
+type plainMetaData MetaData
+
+// UnmarshalJSON decodes MetaData and keeps unknown keys in Extra
+func (m *MetaData) UnmarshalJSON(b []byte) error {
+	return unmarshalWithExtra(b, (*plainMetaData)(m), &m.Extra)
+}
+
+// MarshalJSON encodes MetaData with keys in Extra
+func (m MetaData) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra((*plainMetaData)(&m), m.Extra)
+}
+
+type plainAirConfig AirConfig
+
+// UnmarshalJSON decodes AirConfig and keeps unknown keys in Extra
+func (ac *AirConfig) UnmarshalJSON(b []byte) error {
+	return unmarshalWithExtra(b, (*plainAirConfig)(ac), &ac.Extra)
+}
+
+// MarshalJSON encodes AirConfig with keys in Extra
+func (ac AirConfig) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra((*plainAirConfig)(&ac), ac.Extra)
+}
+
+// GroupConfigs converts AirConfig into key-value pairs for UpdateGroupConfigurations
+func (ac *AirConfig) GroupConfigs() []GroupConfig {
+	return groupConfigs(ac)
+}
+
+// AirConfig returns the configuration in the SoracomAir namespace
+func (g *Group) AirConfig() (*AirConfig, error) {
+	var c AirConfig
+	if err := g.namespaceConfig(ConfigNamespaceAir, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// BeamEntryPointConfig holds a configuration of a SORACOM Beam entry point of any type.
+// Fields not used by the type of the entry point are empty. Use Group.BeamHTTPConfig() etc. to get one of a known type.
+type BeamEntryPointConfig struct {
+	Name                  string                  `json:"name,omitempty"`
+	Destination           string                  `json:"destination,omitempty"`
+	Enabled               bool                    `json:"enabled"`
+	AddSubscriberHeader   bool                    `json:"addSubscriberHeader,omitempty"`
+	AddSignature          bool                    `json:"addSignature,omitempty"`
+	PSK                   string                  `json:"psk,omitempty"`
+	CustomHeaders         map[string]CustomHeader `json:"customHeaders,omitempty"`
+	Username              string                  `json:"username,omitempty"`
+	Password              string                  `json:"password,omitempty"`
+	UseClientCertificates string                  `json:"useClientCert,omitempty"`
+	ClientCertificates    map[string]ClientCerts  `json:"clientCerts,omitempty"`
+	ContentType           string                  `json:"contentType,omitempty"`
+
+	// Extra holds keys not known to this SDK
+	Extra map[string]interface{} `json:"-"`
+}
+
+type plainBeamEntryPointConfig BeamEntryPointConfig
+
+// UnmarshalJSON decodes BeamEntryPointConfig and keeps unknown keys in Extra
+func (c *BeamEntryPointConfig) UnmarshalJSON(b []byte) error {
+	return unmarshalWithExtra(b, (*plainBeamEntryPointConfig)(c), &c.Extra)
+}
+
+// MarshalJSON encodes BeamEntryPointConfig with keys in Extra
+func (c BeamEntryPointConfig) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra((*plainBeamEntryPointConfig)(&c), c.Extra)
+}
+
+// BeamEntryPoints holds configurations of SORACOM Beam entry points by their keys, e.g. BeamEntryPointTCP
+type BeamEntryPoints map[string]*BeamEntryPointConfig
+
+// GroupConfigs converts BeamEntryPoints into key-value pairs for UpdateGroupConfigurations
+func (b BeamEntryPoints) GroupConfigs() []GroupConfig {
+	return groupConfigs(b)
+}
+
+// BeamEntryPoints returns the configurations in the SoracomBeam namespace
+func (g *Group) BeamEntryPoints() (BeamEntryPoints, error) {
+	var c BeamEntryPoints
+	if err := g.namespaceConfig(ConfigNamespaceBeam, &c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type plainFunnelDestinationConfig FunnelDestinationConfig
+
+// UnmarshalJSON decodes FunnelDestinationConfig and keeps unknown keys in Extra
+func (c *FunnelDestinationConfig) UnmarshalJSON(b []byte) error {
+	return unmarshalWithExtra(b, (*plainFunnelDestinationConfig)(c), &c.Extra)
+}
+
+// MarshalJSON encodes FunnelDestinationConfig with keys in Extra
+func (c FunnelDestinationConfig) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra((*plainFunnelDestinationConfig)(&c), c.Extra)
+}
+
+// FunnelConfig holds configuration parameters for SORACOM Funnel
+type FunnelConfig struct {
+	Enabled       bool                    `json:"enabled"`
+	Destination   FunnelDestinationConfig `json:"destination"`
+	CredentialsID string                  `json:"credentialsId"`
+	ContentType   string                  `json:"contentType,omitempty"`
+
+	// Extra holds keys not known to this SDK
+	Extra map[string]interface{} `json:"-"`
+}
+
+type plainFunnelConfig FunnelConfig
+
+// UnmarshalJSON decodes FunnelConfig and keeps unknown keys in Extra
+func (c *FunnelConfig) UnmarshalJSON(b []byte) error {
+	return unmarshalWithExtra(b, (*plainFunnelConfig)(c), &c.Extra)
+}
+
+// MarshalJSON encodes FunnelConfig with keys in Extra
+func (c FunnelConfig) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra((*plainFunnelConfig)(&c), c.Extra)
+}
+
+// GroupConfigs converts FunnelConfig into key-value pairs for UpdateGroupConfigurations
+func (c *FunnelConfig) GroupConfigs() []GroupConfig {
+	return groupConfigs(c)
+}
+
+// FunnelConfig returns the configuration in the SoracomFunnel namespace
+func (g *Group) FunnelConfig() (*FunnelConfig, error) {
+	var c FunnelConfig
+	if err := g.namespaceConfig(ConfigNamespaceFunnel, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// HarvestConfig holds configuration parameters for SORACOM Harvest
+type HarvestConfig struct {
+	Enabled bool `json:"enabled"`
+
+	// Extra holds keys not known to this SDK
+	Extra map[string]interface{} `json:"-"`
+}
+
+type plainHarvestConfig HarvestConfig
+
+// UnmarshalJSON decodes HarvestConfig and keeps unknown keys in Extra
+func (c *HarvestConfig) UnmarshalJSON(b []byte) error {
+	return unmarshalWithExtra(b, (*plainHarvestConfig)(c), &c.Extra)
+}
+
+// MarshalJSON encodes HarvestConfig with keys in Extra
+func (c HarvestConfig) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra((*plainHarvestConfig)(&c), c.Extra)
+}
+
+// GroupConfigs converts HarvestConfig into key-value pairs for UpdateGroupConfigurations
+func (c *HarvestConfig) GroupConfigs() []GroupConfig {
+	return groupConfigs(c)
+}
+
+// HarvestConfig returns the configuration in the SoracomHarvest namespace
+func (g *Group) HarvestConfig() (*HarvestConfig, error) {
+	var c HarvestConfig
+	if err := g.namespaceConfig(ConfigNamespaceHarvest, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// EndorseConfig holds configuration parameters for SORACOM Endorse
+type EndorseConfig struct {
+	Enabled                bool `json:"enabled"`
+	IncludeIMEI            bool `json:"includeImei"`
+	IncludeIMSI            bool `json:"includeImsi"`
+	IncludeMSISDN          bool `json:"includeMsisdn"`
+	IncludeSIMSerialNumber bool `json:"includeSimSerialNumber"`
+	TokenTimeoutSeconds    int  `json:"tokenTimeoutSeconds,omitempty"`
+
+	// Extra holds keys not known to this SDK
+	Extra map[string]interface{} `json:"-"`
+}
+
+type plainEndorseConfig EndorseConfig
+
+// UnmarshalJSON decodes EndorseConfig and keeps unknown keys in Extra
+func (c *EndorseConfig) UnmarshalJSON(b []byte) error {
+	return unmarshalWithExtra(b, (*plainEndorseConfig)(c), &c.Extra)
+}
+
+// MarshalJSON encodes EndorseConfig with keys in Extra
+func (c EndorseConfig) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra((*plainEndorseConfig)(&c), c.Extra)
+}
+
+// GroupConfigs converts EndorseConfig into key-value pairs for UpdateGroupConfigurations
+func (c *EndorseConfig) GroupConfigs() []GroupConfig {
+	return groupConfigs(c)
+}
+
+// EndorseConfig returns the configuration in the SoracomEndorse namespace
+func (g *Group) EndorseConfig() (*EndorseConfig, error) {
+	var c EndorseConfig
+	if err := g.namespaceConfig(ConfigNamespaceEndorse, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// UnifiedEndpointConfig holds configuration parameters for Unified Endpoint
+type UnifiedEndpointConfig struct {
+	ResponseFormat string `json:"responseFormat,omitempty"`
+
+	// Extra holds keys not known to this SDK, e.g. targets the data is forwarded to
+	Extra map[string]interface{} `json:"-"`
+}
+
+type plainUnifiedEndpointConfig UnifiedEndpointConfig
+
+// UnmarshalJSON decodes UnifiedEndpointConfig and keeps unknown keys in Extra
+func (c *UnifiedEndpointConfig) UnmarshalJSON(b []byte) error {
+	return unmarshalWithExtra(b, (*plainUnifiedEndpointConfig)(c), &c.Extra)
+}
+
+// MarshalJSON encodes UnifiedEndpointConfig with keys in Extra
+func (c UnifiedEndpointConfig) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra((*plainUnifiedEndpointConfig)(&c), c.Extra)
+}
+
+// GroupConfigs converts UnifiedEndpointConfig into key-value pairs for UpdateGroupConfigurations
+func (c *UnifiedEndpointConfig) GroupConfigs() []GroupConfig {
+	return groupConfigs(c)
+}
+
+// UnifiedEndpointConfig returns the configuration in the UnifiedEndpoint namespace
+func (g *Group) UnifiedEndpointConfig() (*UnifiedEndpointConfig, error) {
+	var c UnifiedEndpointConfig
+	if err := g.namespaceConfig(ConfigNamespaceUnifiedEndpoint, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
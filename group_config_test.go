@@ -0,0 +1,148 @@
+package soracom
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGroupConfigAccessors(t *testing.T) {
+	var g Group
+	err := json.Unmarshal([]byte(`{
+		"groupId": "group-1",
+		"configuration": {
+			"SoracomAir": {
+				"useCustomDns": true,
+				"dnsServers": ["8.8.8.8"],
+				"metadata": {"enabled": true, "readonly": false, "allowOrigin": "*", "allowedIps": ["10.0.0.0/8"]},
+				"userdata": "hello",
+				"binaryParserEnabled": false,
+				"binaryParserFormat": "",
+				"locationRegistrationEnabled": true
+			},
+			"SoracomBeam": {
+				"tcp://beam.soracom.io:8023": {"name": "tcp", "destination": "tcps://example.com:1234", "enabled": true, "psk": "secret", "newOption": 1},
+				"http://beam.soracom.io:8888/": {"name": "http", "destination": "https://example.com", "enabled": false, "customHeaders": {"h": {"action": "APPEND", "headerKey": "X-A", "headerValue": "b"}}}
+			},
+			"SoracomFunnel": {
+				"enabled": true,
+				"credentialsId": "cred-1",
+				"destination": {"provider": "aws", "service": "kinesis", "resourceUrl": "https://kinesis.ap-northeast-1.amazonaws.com/stream", "randomizePartitionKey": true},
+				"contentType": "application/json"
+			},
+			"SoracomHarvest": {"enabled": true},
+			"SoracomEndorse": {"enabled": true, "includeImei": true, "includeImsi": true, "includeMsisdn": false, "includeSimSerialNumber": false, "tokenTimeoutSeconds": 600},
+			"UnifiedEndpoint": {"responseFormat": "soracomUnifiedEndpoint", "targets": [{"service": "harvest"}]}
+		}
+	}`), &g)
+	if err != nil {
+		t.Fatalf("failed to decode group: %v", err)
+	}
+
+	air, err := g.AirConfig()
+	if err != nil {
+		t.Fatalf("AirConfig() failed: %v", err)
+	}
+	if !air.UseCustomDNS || air.UserData != "hello" || air.MetaData.AllowOrigin != "*" || air.Extra["locationRegistrationEnabled"] != true || air.MetaData.Extra["allowedIps"] == nil {
+		t.Fatalf("unexpected air config: %+v", air)
+	}
+
+	beam, err := g.BeamEntryPoints()
+	if err != nil {
+		t.Fatalf("BeamEntryPoints() failed: %v", err)
+	}
+	if tcp := beam[BeamEntryPointTCP]; tcp == nil || tcp.PSK != "secret" || !tcp.Enabled || tcp.Extra["newOption"] != float64(1) {
+		t.Fatalf("unexpected beam entry points: %+v", beam)
+	}
+	if h := beam[BeamHTTPEntryPoint("/")]; h == nil || h.CustomHeaders["h"].Key != "X-A" {
+		t.Fatalf("unexpected beam entry points: %+v", beam)
+	}
+
+	funnel, err := g.FunnelConfig()
+	if err != nil {
+		t.Fatalf("FunnelConfig() failed: %v", err)
+	}
+	if funnel.CredentialsID != "cred-1" || funnel.Destination.Service != "kinesis" || funnel.Destination.Extra["randomizePartitionKey"] != true {
+		t.Fatalf("unexpected funnel config: %+v", funnel)
+	}
+
+	harvest, err := g.HarvestConfig()
+	if err != nil || !harvest.Enabled {
+		t.Fatalf("unexpected harvest config: %+v, %v", harvest, err)
+	}
+	endorse, err := g.EndorseConfig()
+	if err != nil || !endorse.IncludeIMEI || endorse.TokenTimeoutSeconds != 600 {
+		t.Fatalf("unexpected endorse config: %+v, %v", endorse, err)
+	}
+	ue, err := g.UnifiedEndpointConfig()
+	if err != nil || ue.ResponseFormat != "soracomUnifiedEndpoint" || ue.Extra["targets"] == nil {
+		t.Fatalf("unexpected unified endpoint config: %+v, %v", ue, err)
+	}
+
+	_, err = (&Group{}).HarvestConfig()
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for a namespace not configured: %v", err)
+	}
+}
+
+func TestGroupConfigRoundTrip(t *testing.T) {
+	original := map[ConfigNamespace]map[string]interface{}{
+		ConfigNamespaceAir: {
+			"useCustomDns":                true,
+			"dnsServers":                  []interface{}{"8.8.8.8"},
+			"metadata":                    map[string]interface{}{"enabled": true, "readonly": true, "allowOrigin": "", "allowedIps": []interface{}{"10.0.0.0/8"}},
+			"userdata":                    "",
+			"binaryParserEnabled":         false,
+			"binaryParserFormat":          "",
+			"locationRegistrationEnabled": true,
+		},
+		ConfigNamespaceBeam: {
+			BeamEntryPointMQTT: map[string]interface{}{"name": "mqtt", "destination": "mqtts://example.com", "enabled": true, "useClientCert": "true", "newOption": "x"},
+		},
+		ConfigNamespaceFunnel: {
+			"enabled":       true,
+			"credentialsId": "cred-1",
+			"destination":   map[string]interface{}{"provider": "azure", "service": "eventhubs", "resourceUrl": "https://example.servicebus.windows.net/hub", "extra": "y"},
+			"newOption":     float64(3),
+		},
+		ConfigNamespaceHarvest:         {"enabled": true, "newOption": "z"},
+		ConfigNamespaceEndorse:         {"enabled": true, "includeImei": false, "includeImsi": true, "includeMsisdn": true, "includeSimSerialNumber": false},
+		ConfigNamespaceUnifiedEndpoint: {"responseFormat": "soracomUnifiedEndpoint", "targets": []interface{}{map[string]interface{}{"service": "harvest"}}},
+	}
+
+	ac, _ := newFakeGroupServer(t, &Group{GroupID: "group-1"})
+	src := &Group{GroupID: "source", Configuration: map[ConfigNamespace]interface{}{}}
+	for ns, m := range original {
+		src.Configuration[ns] = m
+	}
+
+	var configs = map[ConfigNamespace][]GroupConfig{}
+	air, _ := src.AirConfig()
+	configs[ConfigNamespaceAir] = air.GroupConfigs()
+	beam, _ := src.BeamEntryPoints()
+	configs[ConfigNamespaceBeam] = beam.GroupConfigs()
+	funnel, _ := src.FunnelConfig()
+	configs[ConfigNamespaceFunnel] = funnel.GroupConfigs()
+	harvest, _ := src.HarvestConfig()
+	configs[ConfigNamespaceHarvest] = harvest.GroupConfigs()
+	endorse, _ := src.EndorseConfig()
+	configs[ConfigNamespaceEndorse] = endorse.GroupConfigs()
+	ue, _ := src.UnifiedEndpointConfig()
+	configs[ConfigNamespaceUnifiedEndpoint] = ue.GroupConfigs()
+
+	var g *Group
+	for ns, c := range configs {
+		var err error
+		g, err = ac.UpdateGroupConfigurations("group-1", string(ns), c)
+		if err != nil {
+			t.Fatalf("UpdateGroupConfigurations() failed: %v", err)
+		}
+	}
+
+	for ns, m := range original {
+		if !reflect.DeepEqual(g.Configuration[ns], m) {
+			t.Errorf("%s was not round-tripped:\n got: %v\nwant: %v", ns, g.Configuration[ns], m)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package soracom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// GroupSpec is the desired state of a group for ApplyGroupSpec.
+// Parts left nil are not managed, i.e. they are neither compared nor changed.
+// Keys present in the group but missing in a managed part are deleted; an empty non-nil part deletes every key.
+type GroupSpec struct {
+	Tags    Tags
+	Air     *AirConfig
+	Beam    BeamEntryPoints
+	Funnel  *FunnelConfig
+	Harvest *HarvestConfig
+}
+
+// GroupChangeAction is the kind of a change to a group
+type GroupChangeAction string
+
+const (
+	// GroupChangeAdd adds a key which is not in the group
+	GroupChangeAdd GroupChangeAction = "add"
+
+	// GroupChangeUpdate changes the value of a key in the group
+	GroupChangeUpdate GroupChangeAction = "update"
+
+	// GroupChangeDelete deletes a key from the group
+	GroupChangeDelete GroupChangeAction = "delete"
+)
+
+// GroupChange is a change to a tag or a configuration key of a group
+type GroupChange struct {
+	// Namespace is the namespace of the configuration key, or empty for tags
+	Namespace ConfigNamespace
+	Key       string
+	Action    GroupChangeAction
+
+	// Before and After hold the values decoded as generic JSON. Before is nil for additions and After is nil for deletions.
+	Before interface{}
+	After  interface{}
+
+	// Applied reports whether the change has been made by ApplyGroupSpec
+	Applied bool
+}
+
+// GroupPlan holds the changes needed to bring a group to a GroupSpec, ordered by namespace and key, with tags first
+type GroupPlan struct {
+	GroupID string
+	Changes []GroupChange
+}
+
+// Empty reports whether the group already matches the spec
+func (p *GroupPlan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String renders the plan one change per line, prefixed with "+", "~" or "-". Secrets such as PSKs and passwords are redacted.
+func (p *GroupPlan) String() string {
+	if p.Empty() {
+		return "group " + p.GroupID + ": no changes\n"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "group %s: %d changes\n", p.GroupID, len(p.Changes))
+	for _, c := range p.Changes {
+		where := "tags"
+		if c.Namespace != "" {
+			where = string(c.Namespace)
+		}
+		switch c.Action {
+		case GroupChangeAdd:
+			fmt.Fprintf(&sb, "+ %s %s: %s\n", where, c.Key, formatPlanValue(c.Key, c.After))
+		case GroupChangeUpdate:
+			fmt.Fprintf(&sb, "~ %s %s: %s -> %s\n", where, c.Key, formatPlanValue(c.Key, c.Before), formatPlanValue(c.Key, c.After))
+		case GroupChangeDelete:
+			fmt.Fprintf(&sb, "- %s %s: %s\n", where, c.Key, formatPlanValue(c.Key, c.Before))
+		}
+	}
+	return sb.String()
+}
+
+// formatPlanValue renders a value as JSON, redacting it as logs do based on its key
+func formatPlanValue(key string, v interface{}) string {
+	var m map[string]interface{}
+	if err := decodeConfigValue(map[string]interface{}{key: v}, &m); err != nil {
+		return fmt.Sprint(v)
+	}
+	b, err := json.Marshal(redactJSONValue(m, false, nil).(map[string]interface{})[key])
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// normalizedConfig decodes a configuration value through the typed configuration v points to,
+// so that values are compared in the form the SDK writes them
+func normalizedConfig(value interface{}, v interface{}) (map[string]interface{}, error) {
+	if err := decodeConfigValue(value, v); err != nil {
+		return nil, err
+	}
+	m := map[string]interface{}{}
+	for _, c := range groupConfigs(v) {
+		m[c.Key] = c.Value
+	}
+	return m, nil
+}
+
+// diffKeys compares current and desired values and appends changes sorted by key.
+// present holds keys which actually exist in the group, as only those can be deleted.
+func diffKeys(changes []GroupChange, namespace ConfigNamespace, present map[string]bool, current, desired map[string]interface{}) []GroupChange {
+	keys := make([]string, 0, len(current)+len(desired))
+	for k := range desired {
+		keys = append(keys, k)
+	}
+	for k := range present {
+		if _, ok := desired[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		after, wanted := desired[k]
+		before, exists := current[k]
+		switch {
+		case !wanted:
+			changes = append(changes, GroupChange{Namespace: namespace, Key: k, Action: GroupChangeDelete, Before: before})
+		case !present[k]:
+			changes = append(changes, GroupChange{Namespace: namespace, Key: k, Action: GroupChangeAdd, After: after})
+		case !exists || !reflect.DeepEqual(before, after):
+			changes = append(changes, GroupChange{Namespace: namespace, Key: k, Action: GroupChangeUpdate, Before: before, After: after})
+		}
+	}
+	return changes
+}
+
+// DiffGroup computes the changes needed to bring group to spec without calling the API
+func DiffGroup(group *Group, spec *GroupSpec) (*GroupPlan, error) {
+	plan := &GroupPlan{GroupID: group.GroupID}
+
+	if spec.Tags != nil {
+		current := map[string]interface{}{}
+		present := map[string]bool{}
+		for k, v := range group.Tags {
+			current[k] = v
+			present[k] = true
+		}
+		desired := map[string]interface{}{}
+		for k, v := range spec.Tags {
+			desired[k] = v
+		}
+		plan.Changes = diffKeys(plan.Changes, "", present, current, desired)
+	}
+
+	namespaces := []struct {
+		namespace ConfigNamespace
+		desired   interface{}
+		typed     func() interface{}
+	}{
+		{ConfigNamespaceAir, spec.Air, func() interface{} { return &AirConfig{} }},
+		{ConfigNamespaceBeam, spec.Beam, func() interface{} { return &BeamEntryPoints{} }},
+		{ConfigNamespaceFunnel, spec.Funnel, func() interface{} { return &FunnelConfig{} }},
+		{ConfigNamespaceHarvest, spec.Harvest, func() interface{} { return &HarvestConfig{} }},
+	}
+	for _, n := range namespaces {
+		if reflect.ValueOf(n.desired).IsNil() {
+			continue
+		}
+		desired, err := normalizedConfig(n.desired, n.typed())
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s configuration in spec: %w", n.namespace, err)
+		}
+
+		raw, _ := group.Configuration[n.namespace].(map[string]interface{})
+		present := map[string]bool{}
+		for k := range raw {
+			present[k] = true
+		}
+		current := map[string]interface{}{}
+		if raw != nil {
+			current, err = normalizedConfig(raw, n.typed())
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode %s configuration of group %s: %w", n.namespace, group.GroupID, err)
+			}
+		}
+		plan.Changes = diffKeys(plan.Changes, n.namespace, present, current, desired)
+	}
+	return plan, nil
+}
+
+// ApplyGroupSpecOptions holds options for APIClient.ApplyGroupSpec()
+type ApplyGroupSpecOptions struct {
+	// DryRun only computes the plan without changing the group
+	DryRun bool
+}
+
+// ApplyGroupSpec gets the group, computes the changes needed to bring it to spec and makes them with the fewest API calls:
+// one call updating tags, one call per namespace updating configurations and one call per deleted key.
+// The returned plan reports which changes have been applied, also when an API call fails.
+func (ac *APIClient) ApplyGroupSpec(groupID string, spec *GroupSpec, options *ApplyGroupSpecOptions) (*GroupPlan, error) {
+	return ac.ApplyGroupSpecWithContext(context.Background(), groupID, spec, options)
+}
+
+// ApplyGroupSpecWithContext is the context-aware version of ApplyGroupSpec.
+func (ac *APIClient) ApplyGroupSpecWithContext(ctx context.Context, groupID string, spec *GroupSpec, options *ApplyGroupSpecOptions) (*GroupPlan, error) {
+	group, err := ac.GetGroupWithContext(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := DiffGroup(group, spec)
+	if err != nil {
+		return nil, err
+	}
+	if options != nil && options.DryRun {
+		return plan, nil
+	}
+	return plan, ac.applyGroupPlan(ctx, plan)
+}
+
+func (ac *APIClient) applyGroupPlan(ctx context.Context, plan *GroupPlan) error {
+	// changes are ordered by namespace, so updates of a namespace are batched into one call
+	for i := 0; i < len(plan.Changes); {
+		namespace := plan.Changes[i].Namespace
+		j := i
+		var batch []int
+		var tags []Tag
+		var configs []GroupConfig
+		for ; j < len(plan.Changes) && plan.Changes[j].Namespace == namespace; j++ {
+			c := plan.Changes[j]
+			if c.Action == GroupChangeDelete {
+				continue
+			}
+			batch = append(batch, j)
+			if namespace == "" {
+				tags = append(tags, Tag{TagName: c.Key, TagValue: fmt.Sprint(c.After)})
+			} else {
+				configs = append(configs, GroupConfig{Key: c.Key, Value: c.After})
+			}
+		}
+
+		if len(batch) > 0 {
+			var err error
+			if namespace == "" {
+				_, err = ac.UpdateGroupTagsWithContext(ctx, plan.GroupID, tags)
+			} else {
+				_, err = ac.UpdateGroupConfigurationsWithContext(ctx, plan.GroupID, string(namespace), configs)
+			}
+			if err != nil {
+				return err
+			}
+			for _, k := range batch {
+				plan.Changes[k].Applied = true
+			}
+		}
+
+		for k := i; k < j; k++ {
+			c := &plan.Changes[k]
+			if c.Action != GroupChangeDelete {
+				continue
+			}
+			var err error
+			if namespace == "" {
+				err = ac.DeleteGroupTagWithContext(ctx, plan.GroupID, c.Key)
+			} else {
+				_, err = ac.DeleteGroupConfigurationWithContext(ctx, plan.GroupID, string(namespace), c.Key)
+			}
+			if err != nil {
+				return err
+			}
+			c.Applied = true
+		}
+		i = j
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package soracom
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newSpecTestGroup() *Group {
+	return &Group{
+		GroupID: "group-1",
+		Tags:    Tags{"name": "old", "obsolete": "x"},
+		Configuration: map[ConfigNamespace]interface{}{
+			ConfigNamespaceAir: map[string]interface{}{
+				"useCustomDns": false,
+				"dnsServers":   nil,
+				"metadata":     map[string]interface{}{"enabled": false, "readonly": false, "allowOrigin": ""},
+				"userdata":     "old",
+			},
+			ConfigNamespaceBeam: map[string]interface{}{
+				BeamEntryPointTCP: map[string]interface{}{"name": "tcp", "destination": "tcps://old.example.com:1234", "enabled": true, "addSignature": false, "psk": "old-secret"},
+				BeamEntryPointUDP: map[string]interface{}{"name": "udp", "destination": "https://example.com/udp", "enabled": true},
+			},
+			ConfigNamespaceFunk: map[string]interface{}{"enabled": true},
+		},
+	}
+}
+
+func newTestGroupSpec() *GroupSpec {
+	return &GroupSpec{
+		Tags: Tags{"name": "new"},
+		Air:  &AirConfig{UserData: "new"},
+		Beam: BeamEntryPoints{
+			BeamEntryPointTCP:  {Name: "tcp", Destination: "tcps://new.example.com:1234", Enabled: true, PSK: "new-secret"},
+			BeamEntryPointMQTT: {Name: "mqtt", Destination: "mqtts://example.com", Enabled: true},
+		},
+		Harvest: &HarvestConfig{Enabled: true},
+	}
+}
+
+func TestDiffGroup(t *testing.T) {
+	plan, err := DiffGroup(newSpecTestGroup(), newTestGroupSpec())
+	if err != nil {
+		t.Fatalf("DiffGroup() failed: %v", err)
+	}
+
+	var got []string
+	for _, c := range plan.Changes {
+		got = append(got, string(c.Action)+" "+string(c.Namespace)+" "+c.Key)
+	}
+	want := []string{
+		"update  name",
+		"delete  obsolete",
+		"add SoracomAir binaryParserEnabled",
+		"add SoracomAir binaryParserFormat",
+		"update SoracomAir userdata",
+		"add SoracomBeam mqtt://beam.soracom.io:1883",
+		"update SoracomBeam tcp://beam.soracom.io:8023",
+		"delete SoracomBeam udp://beam.soracom.io:23080",
+		"add SoracomHarvest enabled",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected plan:\n got: %q\nwant: %q", got, want)
+	}
+
+	s := plan.String()
+	if strings.Contains(s, "secret") || !strings.Contains(s, `~ SoracomAir userdata: "old" -> "new"`) || !strings.Contains(s, "- tags obsolete") {
+		t.Fatalf("unexpected plan output:\n%s", s)
+	}
+
+	plan, err = DiffGroup(newSpecTestGroup(), &GroupSpec{})
+	if err != nil || !plan.Empty() {
+		t.Fatalf("empty spec should manage nothing: %+v, %v", plan, err)
+	}
+	plan, err = DiffGroup(newSpecTestGroup(), &GroupSpec{Beam: BeamEntryPoints{}})
+	if err != nil || len(plan.Changes) != 2 || plan.Changes[0].Action != GroupChangeDelete || plan.Changes[1].Action != GroupChangeDelete {
+		t.Fatalf("empty Beam spec should delete all entry points: %+v, %v", plan, err)
+	}
+}
+
+func TestApplyGroupSpec(t *testing.T) {
+	ac, s := newFakeGroupServer(t, newSpecTestGroup())
+	spec := newTestGroupSpec()
+
+	plan, err := ac.ApplyGroupSpec("group-1", spec, &ApplyGroupSpecOptions{DryRun: true})
+	if err != nil || plan.Empty() {
+		t.Fatalf("ApplyGroupSpec() dry run failed: %+v, %v", plan, err)
+	}
+	if len(s.calls) != 0 {
+		t.Fatalf("dry run should not change the group: %v", s.calls)
+	}
+	for _, c := range plan.Changes {
+		if c.Applied {
+			t.Fatalf("dry run should not apply changes: %+v", c)
+		}
+	}
+
+	plan, err = ac.ApplyGroupSpec("group-1", spec, nil)
+	if err != nil {
+		t.Fatalf("ApplyGroupSpec() failed: %v", err)
+	}
+	for _, c := range plan.Changes {
+		if !c.Applied {
+			t.Fatalf("change not applied: %+v", c)
+		}
+	}
+	want := []string{
+		"PUT /v1/groups/group-1/tags",
+		"DELETE /v1/groups/group-1/tags/obsolete",
+		"PUT /v1/groups/group-1/configuration/SoracomAir",
+		"PUT /v1/groups/group-1/configuration/SoracomBeam",
+		"DELETE /v1/groups/group-1/configuration/SoracomBeam/" + percentEncoding(BeamEntryPointUDP),
+		"PUT /v1/groups/group-1/configuration/SoracomHarvest",
+	}
+	if !reflect.DeepEqual(s.calls, want) {
+		t.Fatalf("unexpected calls:\n got: %q\nwant: %q", s.calls, want)
+	}
+
+	g, err := ac.GetGroup("group-1")
+	if err != nil {
+		t.Fatalf("GetGroup() failed: %v", err)
+	}
+	if g.Configuration[ConfigNamespaceFunk] == nil {
+		t.Fatalf("namespaces not in spec should be left as they are: %v", g.Configuration)
+	}
+	plan, err = DiffGroup(g, spec)
+	if err != nil || !plan.Empty() {
+		t.Fatalf("group should match the spec after applying it:\n%s", plan)
+	}
+
+	s.calls = nil
+	plan, err = ac.ApplyGroupSpec("group-1", spec, nil)
+	if err != nil || !plan.Empty() || len(s.calls) != 0 {
+		t.Fatalf("applying the same spec again should do nothing: %+v, %v, %v", plan, err, s.calls)
+	}
+}
@@ -0,0 +1,40 @@
+package soracom
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ErrNoSession is the reason for subscribers skipped by LockSubscribersToSessionIMEI because they have no online session to lock to
+var ErrNoSession = fmt.Errorf("%w: no online session with an IMEI", ErrSkipSubscriber)
+
+// sessionIMEIs records the session IMEI of every subscriber yielded by a source
+type sessionIMEIs struct {
+	SubscriberSource
+	imeis sync.Map
+}
+
+func (s *sessionIMEIs) Value() Subscriber {
+	sub := s.SubscriberSource.Value()
+	if ss := sub.SessionStatus; ss != nil && ss.Online && ss.IMEI != "" {
+		s.imeis.Store(sub.IMSI, ss.IMEI)
+	}
+	return sub
+}
+
+// LockSubscribersToSessionIMEI locks every online subscriber yielded by src to the IMEI in its SessionStatus using RunBulk.
+// Subscribers without an online session are reported as skipped with ErrNoSession and are not recorded in the checkpoint file,
+// so that they are locked if they are online when the run is resumed.
+// src must yield subscribers with SessionStatus, e.g. a SubscriberIterator returned by IterateSubscribers.
+func (ac *APIClient) LockSubscribersToSessionIMEI(ctx context.Context, src SubscriberSource, options *BulkOptions) (*BulkReport, error) {
+	s := &sessionIMEIs{SubscriberSource: src}
+	return RunBulk(ctx, s, func(ctx context.Context, imsi string) error {
+		imei, ok := s.imeis.Load(imsi)
+		if !ok {
+			return ErrNoSession
+		}
+		_, err := ac.SetSubscriberIMEILockWithContext(ctx, imsi, imei.(string))
+		return err
+	}, options)
+}
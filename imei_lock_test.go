@@ -0,0 +1,120 @@
+package soracom
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSubscriberIMEILock(t *testing.T) {
+	var mu sync.Mutex
+	bodies := map[string]string{}
+	ac := newIteratorTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		imsi := strings.Split(r.URL.Path, "/")[3]
+		mu.Lock()
+		bodies[imsi+" "+r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]] = body["imei"]
+		mu.Unlock()
+
+		sub := Subscriber{IMSI: imsi}
+		if strings.HasSuffix(r.URL.Path, "/set_imei_lock") {
+			sub.IMEILock = &IMEILock{IMEI: body["imei"]}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&sub)
+	}))
+
+	sub, err := ac.SetSubscriberIMEILock("001010000000001", "353000000000001")
+	if err != nil || sub.IMEILock == nil || sub.IMEILock.IMEI != "353000000000001" {
+		t.Fatalf("SetSubscriberIMEILock() failed: %+v, %v", sub, err)
+	}
+	_, err = ac.SetSubscriberIMEILock("001010000000002", "")
+	if err != nil {
+		t.Fatalf("SetSubscriberIMEILock() failed: %v", err)
+	}
+	if imei, ok := bodies["001010000000002 set_imei_lock"]; !ok || imei != "" {
+		t.Fatalf("IMEI should be omitted to lock to the current IMEI: %v", bodies)
+	}
+	sub, err = ac.UnsetSubscriberIMEILock("001010000000001")
+	if err != nil || sub.IMEILock != nil {
+		t.Fatalf("UnsetSubscriberIMEILock() failed: %+v, %v", sub, err)
+	}
+
+	bodies = map[string]string{}
+	subs := &sliceSubscriberSource{subs: []Subscriber{
+		{IMSI: "001010000000001", SessionStatus: &SessionStatus{Online: true, IMEI: "353000000000001"}},
+		{IMSI: "001010000000002", SessionStatus: &SessionStatus{Online: false, IMEI: "353000000000002"}},
+		{IMSI: "001010000000003"},
+	}}
+	report, err := ac.LockSubscribersToSessionIMEI(context.Background(), subs, nil)
+	if err != nil {
+		t.Fatalf("LockSubscribersToSessionIMEI() failed: %v", err)
+	}
+	if len(bodies) != 1 || bodies["001010000000001 set_imei_lock"] != "353000000000001" {
+		t.Fatalf("unexpected requests: %v", bodies)
+	}
+	if report.Count(BulkResultSucceeded) != 1 || report.Count(BulkResultSkipped) != 2 || !errors.Is(report.Results[2].Err, ErrNoSession) {
+		t.Fatalf("unexpected results: %+v", report.Results)
+	}
+}
+
+func TestLockSubscribersToSessionIMEIResume(t *testing.T) {
+	var mu sync.Mutex
+	var locked []string
+	ac := newIteratorTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		imsi := strings.Split(r.URL.Path, "/")[3]
+		mu.Lock()
+		locked = append(locked, imsi)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&Subscriber{IMSI: imsi})
+	}))
+	options := &BulkOptions{Concurrency: 1, CheckpointFile: filepath.Join(t.TempDir(), "checkpoint.jsonl")}
+
+	report, err := ac.LockSubscribersToSessionIMEI(context.Background(), &sliceSubscriberSource{subs: []Subscriber{
+		{IMSI: "001010000000001", SessionStatus: &SessionStatus{Online: true, IMEI: "353000000000001"}},
+		{IMSI: "001010000000002"},
+	}}, options)
+	if err != nil || !errors.Is(report.Results[1].Err, ErrNoSession) {
+		t.Fatalf("LockSubscribersToSessionIMEI() failed: %+v, %v", report, err)
+	}
+
+	// the subscriber without a session has come online before the run is resumed
+	report, err = ac.LockSubscribersToSessionIMEI(context.Background(), &sliceSubscriberSource{subs: []Subscriber{
+		{IMSI: "001010000000001", SessionStatus: &SessionStatus{Online: true, IMEI: "353000000000001"}},
+		{IMSI: "001010000000002", SessionStatus: &SessionStatus{Online: true, IMEI: "353000000000002"}},
+	}}, options)
+	if err != nil {
+		t.Fatalf("LockSubscribersToSessionIMEI() failed: %v", err)
+	}
+	if !errors.Is(report.Results[0].Err, ErrAlreadyProcessed) || report.Results[1].Status != BulkResultSucceeded {
+		t.Fatalf("subscribers without a session should be processed when resumed: %+v", report.Results)
+	}
+	if len(locked) != 2 || locked[0] != "001010000000001" || locked[1] != "001010000000002" {
+		t.Fatalf("unexpected requests: %v", locked)
+	}
+}
+
+type sliceSubscriberSource struct {
+	subs []Subscriber
+	i    int
+}
+
+func (s *sliceSubscriberSource) Next() bool {
+	s.i++
+	return s.i <= len(s.subs)
+}
+
+func (s *sliceSubscriberSource) Value() Subscriber {
+	return s.subs[s.i-1]
+}
+
+func (s *sliceSubscriberSource) Err() error {
+	return nil
+}
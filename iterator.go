@@ -0,0 +1,221 @@
+package soracom
+
+import (
+	"context"
+	"errors"
+)
+
+// errPaginationNotAdvanced is returned by iterators if the API returns the same page again
+var errPaginationNotAdvanced = errors.New("pagination did not advance")
+
+// pageIterator walks pages of a list API lazily. A page is fetched only when all items in the previous page have been consumed.
+type pageIterator struct {
+	ctx context.Context
+
+	// fetch loads the page starting after lastEvaluatedKey and returns the number of items in it and the key for the next page
+	fetch func(ctx context.Context, lastEvaluatedKey string) (n int, next string, err error)
+
+	next    string
+	fetched bool
+	n       int
+	i       int
+	err     error
+
+	// decodeErrors collects items which could not be decoded by clients with lenient decoding enabled
+	decodeErrors DecodeErrors
+}
+
+func newPageIterator(ctx context.Context, lastEvaluatedKey string) pageIterator {
+	return pageIterator{ctx: ctx, next: lastEvaluatedKey}
+}
+
+func (p *pageIterator) advance() bool {
+	for p.err == nil {
+		if p.fetched && p.i+1 < p.n {
+			p.i++
+			return true
+		}
+		if p.fetched && p.next == "" {
+			return false
+		}
+
+		key := p.next
+		n, next, err := p.fetch(p.ctx, key)
+		if errs, ok := err.(DecodeErrors); ok {
+			p.decodeErrors = append(p.decodeErrors, errs...)
+			err = nil
+		}
+		if err != nil {
+			p.err = err
+			return false
+		}
+		if next != "" && next == key {
+			p.err = errPaginationNotAdvanced
+			return false
+		}
+		p.fetched, p.n, p.i, p.next = true, n, -1, next
+	}
+	return false
+}
+
+// SubscriberIterator iterates over subscribers in all pages of a list API.
+//
+//	it := client.IterateSubscribers(ctx, nil)
+//	for it.Next() {
+//		s := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type SubscriberIterator struct {
+	pageIterator
+	page []Subscriber
+}
+
+// Next advances the iterator to the next subscriber, fetching the next page if needed. It returns false when there are no more subscribers or an error occurs.
+func (it *SubscriberIterator) Next() bool {
+	return it.advance()
+}
+
+// Value returns the current subscriber
+func (it *SubscriberIterator) Value() Subscriber {
+	return it.page[it.i]
+}
+
+// Err returns the error which stopped the iteration, if any
+func (it *SubscriberIterator) Err() error {
+	return it.err
+}
+
+// DecodeErrors returns errors for subscribers skipped so far by a client with lenient decoding enabled
+func (it *SubscriberIterator) DecodeErrors() DecodeErrors {
+	return it.decodeErrors
+}
+
+// GroupIterator iterates over groups in all pages of ListGroups
+type GroupIterator struct {
+	pageIterator
+	page []Group
+}
+
+// Next advances the iterator to the next group, fetching the next page if needed. It returns false when there are no more groups or an error occurs.
+func (it *GroupIterator) Next() bool {
+	return it.advance()
+}
+
+// Value returns the current group
+func (it *GroupIterator) Value() Group {
+	return it.page[it.i]
+}
+
+// Err returns the error which stopped the iteration, if any
+func (it *GroupIterator) Err() error {
+	return it.err
+}
+
+// DecodeErrors returns errors for groups skipped so far by a client with lenient decoding enabled
+func (it *GroupIterator) DecodeErrors() DecodeErrors {
+	return it.decodeErrors
+}
+
+// SessionEventIterator iterates over session events in all pages of ListSessionEvents
+type SessionEventIterator struct {
+	pageIterator
+	page []SessionEvent
+}
+
+// Next advances the iterator to the next session event, fetching the next page if needed. It returns false when there are no more events or an error occurs.
+func (it *SessionEventIterator) Next() bool {
+	return it.advance()
+}
+
+// Value returns the current session event
+func (it *SessionEventIterator) Value() SessionEvent {
+	return it.page[it.i]
+}
+
+// Err returns the error which stopped the iteration, if any
+func (it *SessionEventIterator) Err() error {
+	return it.err
+}
+
+// DecodeErrors returns errors for session events skipped so far by a client with lenient decoding enabled
+func (it *SessionEventIterator) DecodeErrors() DecodeErrors {
+	return it.decodeErrors
+}
+
+func nextKey(pk *PaginationKeys) string {
+	if pk == nil {
+		return ""
+	}
+	return pk.Next
+}
+
+// IterateSubscribers returns an iterator over all subscribers matching options.
+// options.Limit is used as the page size and options.LastEvaluatedKey as the starting point.
+func (ac *APIClient) IterateSubscribers(ctx context.Context, options *ListSubscribersOptions) *SubscriberIterator {
+	var o ListSubscribersOptions
+	if options != nil {
+		o = *options
+	}
+	it := &SubscriberIterator{pageIterator: newPageIterator(ctx, o.LastEvaluatedKey)}
+	it.fetch = func(ctx context.Context, lastEvaluatedKey string) (int, string, error) {
+		o.LastEvaluatedKey = lastEvaluatedKey
+		subs, pk, err := ac.ListSubscribersWithContext(ctx, &o)
+		it.page = subs
+		return len(subs), nextKey(pk), err
+	}
+	return it
+}
+
+// IterateGroups returns an iterator over all groups matching options.
+// options.Limit is used as the page size and options.LastEvaluatedKey as the starting point.
+func (ac *APIClient) IterateGroups(ctx context.Context, options *ListGroupsOptions) *GroupIterator {
+	var o ListGroupsOptions
+	if options != nil {
+		o = *options
+	}
+	it := &GroupIterator{pageIterator: newPageIterator(ctx, o.LastEvaluatedKey)}
+	it.fetch = func(ctx context.Context, lastEvaluatedKey string) (int, string, error) {
+		o.LastEvaluatedKey = lastEvaluatedKey
+		groups, pk, err := ac.ListGroupsWithContext(ctx, &o)
+		it.page = groups
+		return len(groups), nextKey(pk), err
+	}
+	return it
+}
+
+// IterateSubscribersInGroup returns an iterator over all subscribers in a group.
+// options.Limit is used as the page size and options.LastEvaluatedKey as the starting point.
+func (ac *APIClient) IterateSubscribersInGroup(ctx context.Context, groupID string, options *ListSubscribersInGroupOptions) *SubscriberIterator {
+	var o ListSubscribersInGroupOptions
+	if options != nil {
+		o = *options
+	}
+	it := &SubscriberIterator{pageIterator: newPageIterator(ctx, o.LastEvaluatedKey)}
+	it.fetch = func(ctx context.Context, lastEvaluatedKey string) (int, string, error) {
+		o.LastEvaluatedKey = lastEvaluatedKey
+		subs, pk, err := ac.ListSubscribersInGroupWithContext(ctx, groupID, &o)
+		it.page = subs
+		return len(subs), nextKey(pk), err
+	}
+	return it
+}
+
+// IterateSessionEvents returns an iterator over all session events of a subscriber.
+// options.Limit is used as the page size and options.LastEvaluatedKey as the starting point.
+func (ac *APIClient) IterateSessionEvents(ctx context.Context, imsi string, options *ListSessionEventsOption) *SessionEventIterator {
+	var o ListSessionEventsOption
+	if options != nil {
+		o = *options
+	}
+	it := &SessionEventIterator{pageIterator: newPageIterator(ctx, o.LastEvaluatedKey)}
+	it.fetch = func(ctx context.Context, lastEvaluatedKey string) (int, string, error) {
+		o.LastEvaluatedKey = lastEvaluatedKey
+		events, pk, err := ac.ListSessionEventsWithContext(ctx, imsi, &o)
+		it.page = events
+		return len(events), nextKey(pk), err
+	}
+	return it
+}
@@ -0,0 +1,183 @@
+package soracom
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// pagedServer serves items in pages of the requested limit with Link headers
+type pagedServer struct {
+	mu       sync.Mutex
+	items    []string
+	requests []string
+}
+
+func (s *pagedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.URL.RawQuery)
+
+	q := r.URL.Query()
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit == 0 {
+		limit = len(s.items)
+	}
+	start := 0
+	if lek := q.Get("last_evaluated_key"); lek != "" {
+		start, _ = strconv.Atoi(lek)
+	}
+	end := start + limit
+	if end > len(s.items) {
+		end = len(s.items)
+	}
+	if end < len(s.items) {
+		w.Header().Set("Link", fmt.Sprintf("<%s?last_evaluated_key=%d>; rel=next", r.URL.Path, end))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	body := "["
+	for i, item := range s.items[start:end] {
+		if i > 0 {
+			body += ","
+		}
+		body += item
+	}
+	_, _ = w.Write([]byte(body + "]"))
+}
+
+func newPagedServer(n int, item func(i int) string) *pagedServer {
+	s := &pagedServer{}
+	for i := 0; i < n; i++ {
+		s.items = append(s.items, item(i))
+	}
+	return s
+}
+
+func newIteratorTestClient(t *testing.T, h http.Handler) *APIClient {
+	ts := httptest.NewServer(h)
+	t.Cleanup(ts.Close)
+	ac := NewAPIClient(&APIClientOptions{Endpoint: ts.URL})
+	ac.SetAuthInfo("api-key", "token", "OP0000000000")
+	return ac
+}
+
+func subscriberItem(i int) string {
+	return fmt.Sprintf(`{"imsi":"0010100000000%02d"}`, i)
+}
+
+func TestIterateSubscribers(t *testing.T) {
+	s := newPagedServer(7, subscriberItem)
+	ac := newIteratorTestClient(t, s)
+
+	it := ac.IterateSubscribers(context.Background(), &ListSubscribersOptions{Limit: 3})
+	var imsis []string
+	for it.Next() {
+		imsis = append(imsis, it.Value().IMSI)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("iteration failed: %v", err)
+	}
+	if len(imsis) != 7 || imsis[0] != "001010000000000" || imsis[6] != "001010000000006" {
+		t.Fatalf("unexpected subscribers: %v", imsis)
+	}
+	if len(s.requests) != 3 || s.requests[0] != "limit=3" || s.requests[2] != "limit=3&last_evaluated_key=6" {
+		t.Fatalf("unexpected requests: %v", s.requests)
+	}
+}
+
+func TestIterateSubscribersStopsEarly(t *testing.T) {
+	s := newPagedServer(7, subscriberItem)
+	ac := newIteratorTestClient(t, s)
+
+	it := ac.IterateSubscribers(context.Background(), &ListSubscribersOptions{Limit: 3})
+	for i := 0; i < 4; i++ {
+		if !it.Next() {
+			t.Fatalf("Next() returned false at %d: %v", i, it.Err())
+		}
+	}
+	if len(s.requests) != 2 {
+		t.Fatalf("pages should be fetched lazily: %v", s.requests)
+	}
+}
+
+func TestIterateSubscribersDecodeError(t *testing.T) {
+	s := newPagedServer(4, func(i int) string {
+		if i == 3 {
+			return `{"imsi":1}`
+		}
+		return subscriberItem(i)
+	})
+	ac := newIteratorTestClient(t, s)
+
+	it := ac.IterateSubscribers(context.Background(), &ListSubscribersOptions{Limit: 2})
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if it.Err() == nil || n != 2 {
+		t.Fatalf("expected a decode error after 2 subscribers, got %d, %v", n, it.Err())
+	}
+}
+
+func TestIterateGroupsAndSubscribersInGroup(t *testing.T) {
+	groups := newPagedServer(5, func(i int) string { return fmt.Sprintf(`{"groupId":"group-%d"}`, i) })
+	subs := newPagedServer(3, subscriberItem)
+	mux := http.NewServeMux()
+	mux.Handle("/v1/groups", groups)
+	mux.Handle("/v1/groups/group-0/subscribers", subs)
+	ac := newIteratorTestClient(t, mux)
+
+	git := ac.IterateGroups(context.Background(), &ListGroupsOptions{Limit: 2})
+	n := 0
+	for git.Next() {
+		if git.Value().GroupID != fmt.Sprintf("group-%d", n) {
+			t.Fatalf("unexpected group: %+v", git.Value())
+		}
+		n++
+	}
+	if git.Err() != nil || n != 5 {
+		t.Fatalf("expected 5 groups, got %d, %v", n, git.Err())
+	}
+
+	sit := ac.IterateSubscribersInGroup(context.Background(), "group-0", &ListSubscribersInGroupOptions{Limit: 1})
+	n = 0
+	for sit.Next() {
+		n++
+	}
+	if sit.Err() != nil || n != 3 || len(subs.requests) != 3 {
+		t.Fatalf("expected 3 subscribers in 3 pages, got %d, %v, %v", n, sit.Err(), subs.requests)
+	}
+}
+
+func TestIterateSessionEvents(t *testing.T) {
+	s := newPagedServer(3, func(i int) string { return fmt.Sprintf(`{"imsi":"001010000000001","event":"event-%d"}`, i) })
+	ac := newIteratorTestClient(t, s)
+
+	it := ac.IterateSessionEvents(context.Background(), "001010000000001", &ListSessionEventsOption{Limit: 2})
+	var events []string
+	for it.Next() {
+		events = append(events, it.Value().Event)
+	}
+	if it.Err() != nil || len(events) != 3 || events[2] != "event-2" {
+		t.Fatalf("unexpected events: %v, %v", events, it.Err())
+	}
+}
+
+func TestIteratorDetectsStuckPagination(t *testing.T) {
+	ac := newIteratorTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", "<"+r.URL.Path+"?last_evaluated_key=same>; rel=next")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+
+	it := ac.IterateGroups(context.Background(), nil)
+	for it.Next() {
+	}
+	if it.Err() != errPaginationNotAdvanced {
+		t.Fatalf("expected errPaginationNotAdvanced, got %v", it.Err())
+	}
+}
@@ -0,0 +1,106 @@
+package soracom
+
+import "strings"
+
+// link is a link-value in a Link header (RFC 8288)
+type link struct {
+	url string
+
+	// params holds link-params with lower-cased names. Only the first occurrence of each param is kept.
+	params map[string]string
+}
+
+// parseLinks parses a Link header. Malformed link-values are skipped as far as possible; parseLinks never fails.
+func parseLinks(h string) []link {
+	var links []link
+	p := &linkParser{s: h}
+	for {
+		start := strings.IndexByte(p.s[p.i:], '<')
+		if start < 0 {
+			return links
+		}
+		p.i += start + 1
+		end := strings.IndexByte(p.s[p.i:], '>')
+		if end < 0 {
+			return links
+		}
+		l := link{url: strings.TrimSpace(p.s[p.i : p.i+end]), params: map[string]string{}}
+		p.i += end + 1
+
+		for {
+			p.skipSpaces()
+			if !p.consume(';') {
+				break
+			}
+			p.skipSpaces()
+			name := strings.ToLower(p.token())
+			p.skipSpaces()
+			var value string
+			if p.consume('=') {
+				p.skipSpaces()
+				if p.consume('"') {
+					value = p.quotedString()
+				} else {
+					value = p.token()
+				}
+			}
+			if _, ok := l.params[name]; name != "" && !ok {
+				l.params[name] = value
+			}
+		}
+		links = append(links, l)
+
+		next := strings.IndexByte(p.s[p.i:], ',')
+		if next < 0 {
+			return links
+		}
+		p.i += next + 1
+	}
+}
+
+type linkParser struct {
+	s string
+	i int
+}
+
+func (p *linkParser) skipSpaces() {
+	for p.i < len(p.s) && (p.s[p.i] == ' ' || p.s[p.i] == '\t') {
+		p.i++
+	}
+}
+
+func (p *linkParser) consume(c byte) bool {
+	if p.i < len(p.s) && p.s[p.i] == c {
+		p.i++
+		return true
+	}
+	return false
+}
+
+// token reads characters up to a separator
+func (p *linkParser) token() string {
+	start := p.i
+	for p.i < len(p.s) && !strings.ContainsRune(" \t;,=\"<", rune(p.s[p.i])) {
+		p.i++
+	}
+	return p.s[start:p.i]
+}
+
+// quotedString reads the rest of a quoted-string whose opening quote has been consumed
+func (p *linkParser) quotedString() string {
+	var b strings.Builder
+	for p.i < len(p.s) {
+		c := p.s[p.i]
+		p.i++
+		switch {
+		case c == '"':
+			return b.String()
+		case c == '\\' && p.i < len(p.s):
+			b.WriteByte(p.s[p.i])
+			p.i++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
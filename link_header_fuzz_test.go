@@ -0,0 +1,28 @@
+//go:build go1.18
+// +build go1.18
+
+package soracom
+
+import (
+	"strings"
+	"testing"
+)
+
+func FuzzParseLinkHeader(f *testing.F) {
+	f.Add("</v1/subscribers?last_evaluated_key=001010000000001>; rel=prev, </v1/subscribers?last_evaluated_key=001010000000010>; rel=next")
+	f.Add(`<https://api.soracom.io/v1/groups?last_evaluated_key=g-1>;rel="next last";title="a, \"b\"; c"`)
+	f.Add(`<a>; rel="next`)
+	f.Add(`<<>>;;==,,;rel=\`)
+	f.Fuzz(func(t *testing.T, header string) {
+		pk := parseLinkHeader(header)
+		if pk == nil {
+			if strings.TrimSpace(header) != "" {
+				t.Fatalf("nil keys for non-empty header %q", header)
+			}
+			return
+		}
+		if (pk.Next != "" && pk.NextURL == "") || (pk.Prev != "" && pk.PrevURL == "") {
+			t.Fatalf("keys without URLs for %q: %+v", header, pk)
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package soracom
+
+import "testing"
+
+func TestParseLinkHeader(t *testing.T) {
+	testData := []struct {
+		header   string
+		expected *PaginationKeys
+	}{
+		{"", nil},
+		{
+			"</v1/subscribers?last_evaluated_key=001010000000001>; rel=prev, </v1/subscribers?last_evaluated_key=001010000000010&limit=10>; rel=next",
+			&PaginationKeys{
+				Prev:    "001010000000001",
+				Next:    "001010000000010",
+				PrevURL: "/v1/subscribers?last_evaluated_key=001010000000001",
+				NextURL: "/v1/subscribers?last_evaluated_key=001010000000010&limit=10",
+			},
+		},
+		{
+			`<https://api.soracom.io/v1/groups?a=1,2&last_evaluated_key=g-1>;rel="next last";title="a, \"b\"; c"`,
+			&PaginationKeys{Next: "g-1", NextURL: "https://api.soracom.io/v1/groups?a=1,2&last_evaluated_key=g-1"},
+		},
+		{
+			`<  /v1/groups?last_evaluated_key=g-2 >  ;  title = x ; REL = "Next" ; rel=prev`,
+			&PaginationKeys{Next: "g-2", NextURL: "/v1/groups?last_evaluated_key=g-2"},
+		},
+		{"</v1/subscribers?limit=10>; rel=next", &PaginationKeys{NextURL: "/v1/subscribers?limit=10"}},
+		{"</v1/subscribers?last_evaluated_key=x>", &PaginationKeys{}},
+		{"</v1/subscribers?last_evaluated_key=x>; rel", &PaginationKeys{}},
+		{"</v1/subscribers?last_evaluated_key=x; rel=next", &PaginationKeys{}},
+		{"garbage, <%zz>; rel=next", &PaginationKeys{NextURL: "%zz"}},
+	}
+	for _, data := range testData {
+		pk := parseLinkHeader(data.header)
+		if (pk == nil) != (data.expected == nil) || (pk != nil && *pk != *data.expected) {
+			t.Errorf("parseLinkHeader(%q): expected %+v, got %+v", data.header, data.expected, pk)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package soracom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// LogLevel is the severity of a log record. The values are the same as the levels of log/slog.
+type LogLevel int
+
+// Log levels
+const (
+	LogLevelDebug LogLevel = -4
+	LogLevelInfo  LogLevel = 0
+	LogLevelWarn  LogLevel = 4
+	LogLevelError LogLevel = 8
+)
+
+// String returns the name of the level in the same way as slog.Level does, e.g. "DEBUG" or "WARN+2"
+func (l LogLevel) String() string {
+	name := func(base string, offset LogLevel) string {
+		if offset == 0 {
+			return base
+		}
+		return fmt.Sprintf("%s%+d", base, offset)
+	}
+	switch {
+	case l < LogLevelInfo:
+		return name("DEBUG", l-LogLevelDebug)
+	case l < LogLevelWarn:
+		return name("INFO", l-LogLevelInfo)
+	case l < LogLevelError:
+		return name("WARN", l-LogLevelWarn)
+	default:
+		return name("ERROR", l-LogLevelError)
+	}
+}
+
+// Logger receives structured log records from clients.
+// args are alternating keys and values as in log/slog. A *slog.Logger can be used by converting the level:
+//
+//	logger.Log(ctx, slog.Level(level), msg, args...)
+//
+// Auth headers, tokens, passwords, PSKs and credential secrets are redacted before they are passed to a Logger.
+type Logger interface {
+	Log(ctx context.Context, level LogLevel, msg string, args ...interface{})
+}
+
+// textLogger writes log records as key=value pairs, one record per line
+type textLogger struct {
+	mu    sync.Mutex
+	w     io.Writer
+	level LogLevel
+}
+
+// NewTextLogger creates a Logger which writes records at level or above to w in the key=value format of slog.TextHandler
+func NewTextLogger(w io.Writer, level LogLevel) Logger {
+	return &textLogger{w: w, level: level}
+}
+
+// verboseLogger is used by SetVerbose if no Logger is given
+var verboseLogger = NewTextLogger(os.Stdout, LogLevelDebug)
+
+func (l *textLogger) Log(ctx context.Context, level LogLevel, msg string, args ...interface{}) {
+	if level < l.level {
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("time=" + time.Now().Format(time.RFC3339Nano))
+	b.WriteString(" level=" + level.String())
+	b.WriteString(" msg=" + quoteLogValue(msg))
+	for i := 0; i < len(args); i += 2 {
+		if i+1 >= len(args) {
+			b.WriteString(" !BADKEY=" + quoteLogValue(fmt.Sprint(args[i])))
+			break
+		}
+		b.WriteString(" " + fmt.Sprint(args[i]) + "=" + quoteLogValue(fmt.Sprint(args[i+1])))
+	}
+	b.WriteString("\n")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = io.WriteString(l.w, b.String())
+}
+
+func quoteLogValue(s string) string {
+	if s == "" {
+		return `""`
+	}
+	for _, r := range s {
+		if unicode.IsSpace(r) || r == '=' || r == '"' || !unicode.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
+
+const redacted = "[REDACTED]"
+
+var sensitiveHeaders = []string{"X-Soracom-Api-Key", "X-Soracom-Token", "Authorization", "Cookie", "Set-Cookie"}
+
+// sensitiveKeyParts are parts of JSON keys whose string values must not be logged
+var sensitiveKeyParts = []string{"password", "secret", "token", "psk", "authkey", "apikey", "privatekey", "otpcode"}
+
+func redactHeader(h http.Header) http.Header {
+	c := h.Clone()
+	for _, k := range sensitiveHeaders {
+		if c.Get(k) != "" {
+			c.Set(k, redacted)
+		}
+	}
+	return c
+}
+
+func isSensitiveKey(key string) bool {
+	k := strings.ToLower(key)
+	if strings.HasSuffix(k, "id") {
+		// identifiers such as authKeyId are not secrets
+		return false
+	}
+	for _, p := range sensitiveKeyParts {
+		if strings.Contains(k, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactBody redacts sensitive values in a JSON body. Bodies which are not JSON are returned as they are.
+func redactBody(body []byte) []byte {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return body
+	}
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return body
+	}
+	b, err := json.Marshal(redactJSONValue(v, false, nil))
+	if err != nil {
+		return body
+	}
+	return b
+}
+
+// sensitiveNestedKeys are keys whose string values must not be logged when they appear anywhere inside objects with the parent keys,
+// e.g. the private key of Beam MQTT client certificates and values of Beam custom headers such as Authorization
+var sensitiveNestedKeys = map[string]map[string]bool{
+	"clientCerts":   {"key": true},
+	"customHeaders": {"headerValue": true},
+}
+
+// redactJSONValue redacts sensitive string values in v. Every value in credentials objects is redacted.
+// nested holds keys which are sensitive because of an enclosing object, as listed in sensitiveNestedKeys.
+func redactJSONValue(v interface{}, sensitive bool, nested map[string]bool) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			n := nested
+			if keys, ok := sensitiveNestedKeys[k]; ok {
+				n = keys
+			}
+			t[k] = redactJSONValue(e, sensitive || isSensitiveKey(k) || k == "credentials" || nested[k], n)
+		}
+	case []interface{}:
+		for i, e := range t {
+			t[i] = redactJSONValue(e, sensitive, nested)
+		}
+	case string:
+		if sensitive {
+			return redacted
+		}
+	}
+	return v
+}
+
+func dumpRedactedRequest(req *http.Request, body string) string {
+	r := req.Clone(req.Context())
+	r.Header = redactHeader(req.Header)
+	b := redactBody([]byte(body))
+	r.Body = io.NopCloser(bytes.NewReader(b))
+	r.ContentLength = int64(len(b))
+	dump, err := httputil.DumpRequest(r, true)
+	if err != nil {
+		return err.Error()
+	}
+	return string(dump)
+}
+
+func dumpRedactedResponse(res *http.Response, body []byte) string {
+	r := *res
+	r.Header = redactHeader(res.Header)
+	b := redactBody(body)
+	r.Body = io.NopCloser(bytes.NewReader(b))
+	r.ContentLength = int64(len(b))
+	dump, err := httputil.DumpResponse(&r, true)
+	if err != nil {
+		return err.Error()
+	}
+	return string(dump)
+}
+
+// logRoundTrip sends req with rt and logs the result. Wire dumps of the request and the response are logged as well if verbose is true.
+// body is the request body, which cannot be read from req without consuming it.
+func logRoundTrip(ctx context.Context, logger Logger, verbose bool, rt RoundTripFunc, req *http.Request, body string) (*http.Response, error) {
+	if logger == nil {
+		if !verbose {
+			return rt(req)
+		}
+		logger = verboseLogger
+	}
+
+	var reqDump string
+	if verbose {
+		reqDump = dumpRedactedRequest(req, body)
+	}
+
+	start := time.Now()
+	res, err := rt(req)
+	args := []interface{}{"method", req.Method, "path", req.URL.Path, "latency", time.Since(start)}
+	if err != nil {
+		logger.Log(ctx, LogLevelError, "request failed", append(args, "error", err)...)
+		return nil, err
+	}
+	args = append(args, "status", res.StatusCode)
+
+	var resBody []byte
+	if verbose || res.StatusCode >= http.StatusBadRequest {
+		resBody, err = io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			logger.Log(ctx, LogLevelError, "reading response failed", append(args, "error", err)...)
+			return nil, err
+		}
+		res.Body = io.NopCloser(bytes.NewReader(resBody))
+	}
+
+	level, msg := LogLevelDebug, "request completed"
+	if res.StatusCode >= http.StatusBadRequest {
+		level, msg = LogLevelWarn, "request returned an error"
+		var e struct {
+			Code string `json:"code"`
+		}
+		if json.Unmarshal(resBody, &e) == nil && e.Code != "" {
+			args = append(args, "errorCode", e.Code)
+		}
+	}
+	if verbose {
+		args = append(args, "request", reqDump, "response", dumpRedactedResponse(res, resBody))
+	}
+	logger.Log(ctx, level, msg, args...)
+
+	return res, nil
+}
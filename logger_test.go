@@ -0,0 +1,166 @@
+package soracom
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type logRecord struct {
+	level LogLevel
+	msg   string
+	attrs map[string]string
+}
+
+type recordingLogger struct {
+	mu      sync.Mutex
+	records []logRecord
+}
+
+func (l *recordingLogger) Log(ctx context.Context, level LogLevel, msg string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	attrs := map[string]string{}
+	for i := 0; i+1 < len(args); i += 2 {
+		attrs[fmt.Sprint(args[i])] = fmt.Sprint(args[i+1])
+	}
+	l.records = append(l.records, logRecord{level, msg, attrs})
+}
+
+func TestLogLevelString(t *testing.T) {
+	testData := map[LogLevel]string{
+		LogLevelDebug:     "DEBUG",
+		LogLevelInfo:      "INFO",
+		LogLevelWarn:      "WARN",
+		LogLevelError:     "ERROR",
+		LogLevelWarn + 2:  "WARN+2",
+		LogLevelDebug - 1: "DEBUG-1",
+	}
+	for l, expected := range testData {
+		if l.String() != expected {
+			t.Errorf("expected %s, got %s", expected, l.String())
+		}
+	}
+}
+
+func TestTextLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewTextLogger(&buf, LogLevelInfo)
+	l.Log(context.Background(), LogLevelDebug, "ignored")
+	l.Log(context.Background(), LogLevelWarn, "request returned an error", "status", 404, "errorCode", "SEM0001", "path", "/v1/a b")
+	s := buf.String()
+	if strings.Contains(s, "ignored") {
+		t.Fatalf("record below the level was logged: %s", s)
+	}
+	if !strings.Contains(s, `level=WARN msg="request returned an error" status=404 errorCode=SEM0001 path="/v1/a b"`+"\n") {
+		t.Fatalf("unexpected output: %s", s)
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	body := `{"email":"test@example.com","password":"p@ssw0rd","authKeyId":"keyId-xxx","authKey":"secret-xxx","tokenTimeoutSeconds":86400,` +
+		`"credentials":{"accessKeyId":"AKIA","secretAccessKey":"aws-secret"},"config":[{"key":"psk","value":{"psk":"beam-psk","name":"n"}}]}`
+	s := string(redactBody([]byte(body)))
+	for _, secret := range []string{"p@ssw0rd", "secret-xxx", "AKIA", "aws-secret", "beam-psk"} {
+		if strings.Contains(s, secret) {
+			t.Errorf("%s was not redacted: %s", secret, s)
+		}
+	}
+	for _, kept := range []string{"test@example.com", "keyId-xxx", "86400", `"key":"psk"`, `"name":"n"`} {
+		if !strings.Contains(s, kept) {
+			t.Errorf("%s should not be redacted: %s", kept, s)
+		}
+	}
+
+	// Beam configurations as sent by UpdateBeamMQTTConfig and UpdateBeamHTTPConfig
+	body = toJSON([]GroupConfig{
+		{Key: BeamEntryPointMQTT, Value: &BeamMQTTConfig{Name: "mqtt", ClientCertificates: map[string]ClientCerts{"default": {CA: "ca-pem", Cert: "cert-pem", PrivateKey: "private-key-pem"}}}},
+		{Key: BeamHTTPEntryPoint("/"), Value: &BeamHTTPConfig{Name: "http", CustomHeaders: map[string]CustomHeader{"auth": {Action: "APPEND", Key: "Authorization", Value: "Bearer header-secret"}}}},
+	})
+	s = string(redactBody([]byte(body)))
+	for _, secret := range []string{"private-key-pem", "header-secret"} {
+		if strings.Contains(s, secret) {
+			t.Errorf("%s was not redacted: %s", secret, s)
+		}
+	}
+	for _, kept := range []string{"ca-pem", "cert-pem", `"headerKey":"Authorization"`, `"key":"mqtt://beam.soracom.io:1883"`} {
+		if !strings.Contains(s, kept) {
+			t.Errorf("%s should not be redacted: %s", kept, s)
+		}
+	}
+
+	if string(redactBody([]byte("not json"))) != "not json" {
+		t.Error("non-JSON body should be kept")
+	}
+}
+
+func TestAPIClientLogger(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1/auth" {
+			_, _ = w.Write([]byte(`{"apiKey":"api-key-xxx","token":"token-xxx","operatorId":"OP0000000000"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"code":"SEM0001","message":"not found"}`))
+	}))
+	defer ts.Close()
+
+	l := &recordingLogger{}
+	ac := NewAPIClient(&APIClientOptions{Endpoint: ts.URL, Logger: l})
+	ac.SetVerbose(true)
+	err := ac.Auth("test@example.com", "p@ssw0rd")
+	if err != nil {
+		t.Fatalf("Auth() failed: %v", err)
+	}
+	_, err = ac.GetOperator("OP0000000000")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if len(l.records) != 2 {
+		t.Fatalf("expected 2 records, got %+v", l.records)
+	}
+	r := l.records[0]
+	if r.level != LogLevelDebug || r.attrs["method"] != "POST" || r.attrs["path"] != "/v1/auth" || r.attrs["status"] != "200" || r.attrs["latency"] == "" {
+		t.Fatalf("unexpected record: %+v", r)
+	}
+	r = l.records[1]
+	if r.level != LogLevelWarn || r.attrs["status"] != "404" || r.attrs["errorCode"] != "SEM0001" {
+		t.Fatalf("unexpected record: %+v", r)
+	}
+	for _, r := range l.records {
+		dump := r.attrs["request"] + r.attrs["response"]
+		for _, secret := range []string{"p@ssw0rd", "api-key-xxx", "token-xxx"} {
+			if strings.Contains(dump, secret) {
+				t.Fatalf("%s was not redacted: %s", secret, dump)
+			}
+		}
+	}
+	if !strings.Contains(l.records[1].attrs["request"], "X-Soracom-Token: "+redacted) {
+		t.Fatalf("auth header was not redacted: %s", l.records[1].attrs["request"])
+	}
+}
+
+func TestMetadataClientLogger(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"imsi":"001010000000001"}`))
+	}))
+	defer ts.Close()
+
+	l := &recordingLogger{}
+	mc := NewMetadataClient(&MetadataClientOptions{Endpoint: ts.URL, Logger: l})
+	s, err := mc.GetSubscriber()
+	if err != nil || s.IMSI != "001010000000001" {
+		t.Fatalf("GetSubscriber() failed: %+v, %v", s, err)
+	}
+	if len(l.records) != 1 || l.records[0].attrs["path"] != "/v1/subscriber" || l.records[0].attrs["response"] != "" {
+		t.Fatalf("unexpected records: %+v", l.records)
+	}
+}
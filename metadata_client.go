@@ -1,23 +1,43 @@
 package soracom
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
 // MetadataClient provides an access to SORACOM Metadata Service APIs
 type MetadataClient struct {
-	httpClient *http.Client
-	endpoint   string
-	verbose    bool
+	httpClient  *http.Client
+	roundTrip   RoundTripFunc
+	endpoint    string
+	verbose     int32
+	logger      Logger
+	retryPolicy *RetryPolicy
+
+	validateSpeedClasses bool
 }
 
 // MetadataClientOptions holds options for creating an MetadataClient
 type MetadataClientOptions struct {
 	Endpoint string
 	Client   *http.Client
+
+	// RetryPolicy enables retrying failed API calls. API calls are not retried if nil.
+	RetryPolicy *RetryPolicy
+
+	// Middlewares intercept every HTTP request and response. The first middleware is the outermost one.
+	Middlewares []Middleware
+
+	// Logger receives a log record for every HTTP request. Nothing is logged if nil unless verbose output is enabled.
+	Logger Logger
+
+	// ValidateSpeedClasses makes UpdateSpeedClass get the subscriber first and fail with an error matching ErrUnsupportedSpeedClass
+	// without calling the API if the speed class is not available for the subscription of the subscriber.
+	ValidateSpeedClasses bool
 }
 
 // NewMetadataClient creates an instance of MetadataClient
@@ -33,73 +53,99 @@ func NewMetadataClient(options *MetadataClientOptions) *MetadataClient {
 		endpoint = options.Endpoint
 	}
 
+	var retryPolicy *RetryPolicy
+	var middlewares []Middleware
+	var logger Logger
+	if options != nil {
+		retryPolicy = options.RetryPolicy
+		middlewares = options.Middlewares
+		logger = options.Logger
+	}
+
 	return &MetadataClient{
-		httpClient: hc,
-		endpoint:   endpoint,
+		httpClient:  hc,
+		roundTrip:   chainMiddlewares(hc.Do, middlewares),
+		endpoint:    endpoint,
+		retryPolicy: retryPolicy,
+		logger:      logger,
+
+		validateSpeedClasses: options != nil && options.ValidateSpeedClasses,
 	}
 }
 
-// SetVerbose sets if verbose output is enabled or not
+// SetVerbose sets if verbose output is enabled or not.
+// Verbose output logs redacted wire dumps of requests and responses to the Logger given in options, or to stdout if no Logger is given.
+// It is safe to call SetVerbose while API calls are in flight.
 func (mc *MetadataClient) SetVerbose(verbose bool) {
-	mc.verbose = verbose
+	atomic.StoreInt32(&mc.verbose, boolToInt32(verbose))
 }
 
-func (mc *MetadataClient) callAPI(params *apiParams) (*http.Response, error) {
-	url := mc.endpoint + params.path
-	if params.query != "" {
-		url += "?" + params.query
-	}
-
-	req, err := http.NewRequest(params.method, url, strings.NewReader(params.body))
+func (mc *MetadataClient) callAPI(ctx context.Context, params *apiParams) (*http.Response, error) {
+	res, err := doWithRetry(ctx, mc.retryPolicy, params.method, func() (*http.Response, error) {
+		return mc.sendRequest(ctx, params)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if params.contentType != "" {
-		req.Header.Set("Content-Type", params.contentType)
+	if res.StatusCode >= http.StatusBadRequest {
+		defer res.Body.Close()
+		return nil, NewAPIError(res)
 	}
 
-	if mc.verbose {
-		dumpHTTPRequest(req)
+	return res, nil
+}
+
+func (mc *MetadataClient) sendRequest(ctx context.Context, params *apiParams) (*http.Response, error) {
+	url := mc.endpoint + params.path
+	if params.query != "" {
+		url += "?" + params.query
 	}
 
-	res, err := mc.httpClient.Do(req)
+	req, err := http.NewRequestWithContext(ctx, params.method, url, strings.NewReader(params.body))
 	if err != nil {
 		return nil, err
 	}
 
-	if mc.verbose {
-		dumpHTTPResponse(res)
-		fmt.Println("==========")
-	}
-
-	if res.StatusCode >= http.StatusBadRequest {
-		defer res.Body.Close()
-		return nil, NewAPIError(res)
+	if params.contentType != "" {
+		req.Header.Set("Content-Type", params.contentType)
 	}
 
-	return res, nil
+	return logRoundTrip(ctx, mc.logger, atomic.LoadInt32(&mc.verbose) != 0, mc.roundTrip, req, params.body)
 }
 
 // GetSubscriber gets metadata for the calling subscriber
 func (mc *MetadataClient) GetSubscriber() (*Subscriber, error) {
+	return mc.GetSubscriberWithContext(context.Background())
+}
+
+// GetSubscriberWithContext is the context-aware version of GetSubscriber.
+func (mc *MetadataClient) GetSubscriberWithContext(ctx context.Context) (*Subscriber, error) {
 	params := &apiParams{
 		method: "GET",
 		path:   "/v1/subscriber",
 	}
-	resp, err := mc.callAPI(params)
+	resp, err := mc.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
-
-	return subscriber, nil
+	return parseSubscriber(resp)
 }
 
 // UpdateSpeedClass updates speed class of the calling subscriber.
-func (mc *MetadataClient) UpdateSpeedClass(speedClass string) (*Subscriber, error) {
+func (mc *MetadataClient) UpdateSpeedClass(speedClass SpeedClass) (*Subscriber, error) {
+	return mc.UpdateSpeedClassWithContext(context.Background(), speedClass)
+}
+
+// UpdateSpeedClassWithContext is the context-aware version of UpdateSpeedClass.
+func (mc *MetadataClient) UpdateSpeedClassWithContext(ctx context.Context, speedClass SpeedClass) (*Subscriber, error) {
+	err := mc.validateSpeedClass(ctx, speedClass)
+	if err != nil {
+		return nil, err
+	}
+
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/subscriber/update_speed_class",
@@ -107,19 +153,22 @@ func (mc *MetadataClient) UpdateSpeedClass(speedClass string) (*Subscriber, erro
 		body:        (&updateSpeedClassRequest{SpeedClass: speedClass}).JSON(),
 	}
 
-	resp, err := mc.callAPI(params)
+	resp, err := mc.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
-
-	return subscriber, nil
+	return parseSubscriber(resp)
 }
 
 // EnableTermination enables termination of the calling subscriber.
 func (mc *MetadataClient) EnableTermination() (*Subscriber, error) {
+	return mc.EnableTerminationWithContext(context.Background())
+}
+
+// EnableTerminationWithContext is the context-aware version of EnableTermination.
+func (mc *MetadataClient) EnableTerminationWithContext(ctx context.Context) (*Subscriber, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/subscriber/enable_termination",
@@ -127,19 +176,22 @@ func (mc *MetadataClient) EnableTermination() (*Subscriber, error) {
 		body:        "{}",
 	}
 
-	resp, err := mc.callAPI(params)
+	resp, err := mc.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
-
-	return subscriber, nil
+	return parseSubscriber(resp)
 }
 
 // DisableTermination disables termination of the calling subscriber.
 func (mc *MetadataClient) DisableTermination() (*Subscriber, error) {
+	return mc.DisableTerminationWithContext(context.Background())
+}
+
+// DisableTerminationWithContext is the context-aware version of DisableTermination.
+func (mc *MetadataClient) DisableTerminationWithContext(ctx context.Context) (*Subscriber, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/subscriber/disable_termination",
@@ -147,19 +199,22 @@ func (mc *MetadataClient) DisableTermination() (*Subscriber, error) {
 		body:        "{}",
 	}
 
-	resp, err := mc.callAPI(params)
+	resp, err := mc.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
-
-	return subscriber, nil
+	return parseSubscriber(resp)
 }
 
 // SetExpiredAt sets expiration time of the calling subscriber.
 func (mc *MetadataClient) SetExpiredAt(expiryTime time.Time) (*Subscriber, error) {
+	return mc.SetExpiredAtWithContext(context.Background(), expiryTime)
+}
+
+// SetExpiredAtWithContext is the context-aware version of SetExpiredAt.
+func (mc *MetadataClient) SetExpiredAtWithContext(ctx context.Context, expiryTime time.Time) (*Subscriber, error) {
 	ts := &TimestampMilli{Time: expiryTime}
 	req := &setExpiredAtRequest{
 		ExpiredAt: fmt.Sprint(ts.UnixMilli()),
@@ -171,19 +226,22 @@ func (mc *MetadataClient) SetExpiredAt(expiryTime time.Time) (*Subscriber, error
 		body:        req.JSON(),
 	}
 
-	resp, err := mc.callAPI(params)
+	resp, err := mc.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
-
-	return subscriber, nil
+	return parseSubscriber(resp)
 }
 
 // UnsetExpiredAt unsets expiration time of the calling subscriber.
 func (mc *MetadataClient) UnsetExpiredAt() (*Subscriber, error) {
+	return mc.UnsetExpiredAtWithContext(context.Background())
+}
+
+// UnsetExpiredAtWithContext is the context-aware version of UnsetExpiredAt.
+func (mc *MetadataClient) UnsetExpiredAtWithContext(ctx context.Context) (*Subscriber, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/subscriber/unset_expiry_time",
@@ -191,19 +249,22 @@ func (mc *MetadataClient) UnsetExpiredAt() (*Subscriber, error) {
 		body:        "{}",
 	}
 
-	resp, err := mc.callAPI(params)
+	resp, err := mc.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
-
-	return subscriber, nil
+	return parseSubscriber(resp)
 }
 
 // SetGroup sets a group of the calling subscriber.
 func (mc *MetadataClient) SetGroup(groupID string) (*Subscriber, error) {
+	return mc.SetGroupWithContext(context.Background(), groupID)
+}
+
+// SetGroupWithContext is the context-aware version of SetGroup.
+func (mc *MetadataClient) SetGroupWithContext(ctx context.Context, groupID string) (*Subscriber, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/subscriber/set_group",
@@ -211,19 +272,22 @@ func (mc *MetadataClient) SetGroup(groupID string) (*Subscriber, error) {
 		body:        (&setSubscriberGroupRequest{GroupID: groupID}).JSON(),
 	}
 
-	resp, err := mc.callAPI(params)
+	resp, err := mc.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
-
-	return subscriber, nil
+	return parseSubscriber(resp)
 }
 
 // UnsetGroup unsets group of the calling subscriber.
 func (mc *MetadataClient) UnsetGroup() (*Subscriber, error) {
+	return mc.UnsetGroupWithContext(context.Background())
+}
+
+// UnsetGroupWithContext is the context-aware version of UnsetGroup.
+func (mc *MetadataClient) UnsetGroupWithContext(ctx context.Context) (*Subscriber, error) {
 	params := &apiParams{
 		method:      "POST",
 		path:        "/v1/subscriber/unset_group",
@@ -231,19 +295,22 @@ func (mc *MetadataClient) UnsetGroup() (*Subscriber, error) {
 		body:        "{}",
 	}
 
-	resp, err := mc.callAPI(params)
+	resp, err := mc.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
-
-	return subscriber, nil
+	return parseSubscriber(resp)
 }
 
 // PutTags puts tags on the calling subscriber
 func (mc *MetadataClient) PutTags(tags []Tag) (*Subscriber, error) {
+	return mc.PutTagsWithContext(context.Background(), tags)
+}
+
+// PutTagsWithContext is the context-aware version of PutTags.
+func (mc *MetadataClient) PutTagsWithContext(ctx context.Context, tags []Tag) (*Subscriber, error) {
 	params := &apiParams{
 		method:      "PUT",
 		path:        "/v1/subscriber/tags",
@@ -251,24 +318,27 @@ func (mc *MetadataClient) PutTags(tags []Tag) (*Subscriber, error) {
 		body:        tagsToJSON(tags),
 	}
 
-	resp, err := mc.callAPI(params)
+	resp, err := mc.callAPI(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	subscriber := parseSubscriber(resp)
-
-	return subscriber, nil
+	return parseSubscriber(resp)
 }
 
 // DeleteTag deletes a tag on the calling subscriber
 func (mc *MetadataClient) DeleteTag(tagName string) error {
+	return mc.DeleteTagWithContext(context.Background(), tagName)
+}
+
+// DeleteTagWithContext is the context-aware version of DeleteTag.
+func (mc *MetadataClient) DeleteTagWithContext(ctx context.Context, tagName string) error {
 	params := &apiParams{
 		method: "DELETE",
 		path:   "/v1/subscriber/tags/" + percentEncoding(tagName),
 	}
-	resp, err := mc.callAPI(params)
+	resp, err := mc.callAPI(ctx, params)
 	if err != nil {
 		return err
 	}
@@ -279,11 +349,16 @@ func (mc *MetadataClient) DeleteTag(tagName string) error {
 
 // GetUserdata gets userdata for the calling subscriber's group
 func (mc *MetadataClient) GetUserdata() (string, error) {
+	return mc.GetUserdataWithContext(context.Background())
+}
+
+// GetUserdataWithContext is the context-aware version of GetUserdata.
+func (mc *MetadataClient) GetUserdataWithContext(ctx context.Context) (string, error) {
 	params := &apiParams{
 		method: "GET",
 		path:   "/v1/userdata",
 	}
-	resp, err := mc.callAPI(params)
+	resp, err := mc.callAPI(ctx, params)
 	if err != nil {
 		return "", err
 	}
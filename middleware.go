@@ -0,0 +1,19 @@
+package soracom
+
+import "net/http"
+
+// RoundTripFunc sends an HTTP request and returns its response
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware intercepts HTTP requests sent and responses received by a client.
+// A Middleware receives the next RoundTripFunc in the chain and returns a RoundTripFunc which usually calls it.
+// Requests passed to middlewares already have SORACOM auth headers set.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// chainMiddlewares wraps rt with middlewares. The first middleware is the outermost one, i.e. it sees requests first and responses last.
+func chainMiddlewares(rt RoundTripFunc, middlewares []Middleware) RoundTripFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		rt = middlewares[i](rt)
+	}
+	return rt
+}
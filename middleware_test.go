@@ -0,0 +1,86 @@
+package soracom
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPIClientMiddlewares(t *testing.T) {
+	var correlationID string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		correlationID = r.Header.Get("X-Correlation-Id")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"operatorId":"OP0000000000"}`))
+	}))
+	defer ts.Close()
+
+	var order []string
+	var seenToken string
+	trace := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				order = append(order, name+">")
+				res, err := next(req)
+				order = append(order, "<"+name)
+				return res, err
+			}
+		}
+	}
+	auth := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			seenToken = req.Header.Get("X-Soracom-Token")
+			req.Header.Set("X-Correlation-Id", "correlation-xxx")
+			return next(req)
+		}
+	}
+
+	ac := NewAPIClient(&APIClientOptions{
+		Endpoint:    ts.URL,
+		Middlewares: []Middleware{trace("outer"), auth, trace("inner")},
+	})
+	ac.SetAuthInfo("api-key", "token", "OP0000000000")
+	_, err := ac.GetOperator("OP0000000000")
+	if err != nil {
+		t.Fatalf("GetOperator() failed: %v", err)
+	}
+	if seenToken != "token" {
+		t.Fatalf("auth headers were not set before middlewares ran: %q", seenToken)
+	}
+	if correlationID != "correlation-xxx" {
+		t.Fatalf("header set by a middleware was not sent: %q", correlationID)
+	}
+	if strings.Join(order, " ") != "outer> inner> <inner <outer" {
+		t.Fatalf("unexpected middleware order: %v", order)
+	}
+}
+
+func TestMetadataClientMiddlewares(t *testing.T) {
+	called := false
+	stub := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(bytes.NewBufferString(`{"imsi":"001010000000001"}`)),
+				Request:    req,
+			}, nil
+		}
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer ts.Close()
+
+	mc := NewMetadataClient(&MetadataClientOptions{Endpoint: ts.URL, Middlewares: []Middleware{stub}})
+	s, err := mc.GetSubscriber()
+	if err != nil {
+		t.Fatalf("GetSubscriber() failed: %v", err)
+	}
+	if s.IMSI != "001010000000001" || called {
+		t.Fatalf("middleware did not short-circuit the request: %+v, %v", s, called)
+	}
+}
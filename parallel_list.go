@@ -0,0 +1,167 @@
+package soracom
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultListConcurrency is the number of partitions listed at once if ParallelListOptions.Concurrency is not set
+const defaultListConcurrency = 4
+
+// ParallelListOptions holds options for APIClient.ListSubscribersParallel()
+type ParallelListOptions struct {
+	// Partitions split the inventory into parts which are listed concurrently, e.g. by PartitionByStatus or PartitionByTagValuePrefix.
+	// Partitions may overlap; subscribers are deduplicated by IMSI.
+	Partitions []ListSubscribersOptions
+
+	// Concurrency is the maximum number of partitions listed at once. Defaults to 4.
+	Concurrency int
+}
+
+// PartitionByStatus returns partitions listing subscribers in each of statuses. Other options are copied from base.
+func PartitionByStatus(base ListSubscribersOptions, statuses ...string) []ListSubscribersOptions {
+	partitions := make([]ListSubscribersOptions, 0, len(statuses))
+	for _, s := range statuses {
+		p := base
+		p.StatusFilter = s
+		partitions = append(partitions, p)
+	}
+	return partitions
+}
+
+// PartitionByTagValuePrefix returns partitions listing subscribers whose tag value starts with each of prefixes. Other options are copied from base.
+func PartitionByTagValuePrefix(base ListSubscribersOptions, tagName string, prefixes ...string) []ListSubscribersOptions {
+	partitions := make([]ListSubscribersOptions, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		p := base
+		p.TagName = tagName
+		p.TagValue = prefix
+		p.TagValueMatchMode = MatchModePrefix
+		partitions = append(partitions, p)
+	}
+	return partitions
+}
+
+// SubscriberStream is a merged stream of subscribers listed from multiple partitions concurrently.
+// Subscribers are yielded in no particular order. Close must be called if the stream is not consumed to the end.
+type SubscriberStream struct {
+	cancel context.CancelFunc
+	items  chan Subscriber
+	seen   map[string]struct{}
+	cur    Subscriber
+
+	mu           sync.Mutex
+	closed       bool
+	err          error
+	decodeErrors DecodeErrors
+}
+
+// ListSubscribersParallel lists subscribers in all partitions with bounded concurrency and merges them into a stream.
+// Every partition is walked through all its pages as IterateSubscribers does. The first error stops listing of all partitions.
+func (ac *APIClient) ListSubscribersParallel(ctx context.Context, options *ParallelListOptions) *SubscriberStream {
+	var partitions []ListSubscribersOptions
+	concurrency := defaultListConcurrency
+	if options != nil {
+		partitions = options.Partitions
+		if options.Concurrency > 0 {
+			concurrency = options.Concurrency
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &SubscriberStream{
+		cancel: cancel,
+		items:  make(chan Subscriber, concurrency),
+		seen:   map[string]struct{}{},
+	}
+
+	go func() {
+		defer close(s.items)
+		defer cancel()
+		sem := make(chan struct{}, concurrency)
+		var wg sync.WaitGroup
+		defer wg.Wait()
+		for i := range partitions {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			wg.Add(1)
+			go func(p ListSubscribersOptions) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				s.listPartition(ctx, ac, &p)
+			}(partitions[i])
+		}
+	}()
+
+	return s
+}
+
+func (s *SubscriberStream) listPartition(ctx context.Context, ac *APIClient, p *ListSubscribersOptions) {
+	it := ac.IterateSubscribers(ctx, p)
+	err := func() error {
+		for it.Next() {
+			select {
+			case s.items <- it.Value():
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return it.Err()
+	}()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.decodeErrors = append(s.decodeErrors, it.DecodeErrors()...)
+	if err != nil && s.err == nil && !s.closed {
+		s.err = err
+		s.cancel()
+	}
+}
+
+// Next advances the stream to the next subscriber not yielded yet. It returns false when all partitions have been listed or an error occurs.
+func (s *SubscriberStream) Next() bool {
+	for sub := range s.items {
+		if _, ok := s.seen[sub.IMSI]; ok {
+			continue
+		}
+		s.seen[sub.IMSI] = struct{}{}
+		s.cur = sub
+		return true
+	}
+	return false
+}
+
+// Value returns the current subscriber
+func (s *SubscriberStream) Value() Subscriber {
+	return s.cur
+}
+
+// Err returns the error which stopped the stream, if any
+func (s *SubscriberStream) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// DecodeErrors returns errors for subscribers skipped so far by a client with lenient decoding enabled
+func (s *SubscriberStream) DecodeErrors() DecodeErrors {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.decodeErrors
+}
+
+// Close stops listing and waits for all partitions to stop. It is safe to call Close after the stream has been consumed.
+func (s *SubscriberStream) Close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	s.cancel()
+	for range s.items {
+	}
+}
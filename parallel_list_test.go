@@ -0,0 +1,144 @@
+package soracom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// partitionedServer serves subscribers filtered by status_filter, one subscriber per page
+type partitionedServer struct {
+	mu          sync.Mutex
+	subscribers map[string][]string
+	inFlight    int
+	maxInFlight int
+	requests    int
+	fail        string
+}
+
+func (s *partitionedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.inFlight++
+	s.requests++
+	if s.inFlight > s.maxInFlight {
+		s.maxInFlight = s.inFlight
+	}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+	}()
+	time.Sleep(5 * time.Millisecond)
+
+	q := r.URL.Query()
+	status := q.Get("status_filter")
+	if status == s.fail {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"code":"SEM0001","message":"bad request"}`))
+		return
+	}
+	imsis := s.subscribers[status]
+	i, _ := strconv.Atoi(q.Get("last_evaluated_key"))
+	if i+1 < len(imsis) {
+		w.Header().Set("Link", fmt.Sprintf("</v1/subscribers?last_evaluated_key=%d>; rel=next", i+1))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if i >= len(imsis) {
+		_, _ = w.Write([]byte(`[]`))
+		return
+	}
+	_, _ = w.Write([]byte(`[{"imsi":"` + imsis[i] + `","status":"` + status + `"}]`))
+}
+
+func TestListSubscribersParallel(t *testing.T) {
+	s := &partitionedServer{subscribers: map[string][]string{
+		"active":    {"001", "002", "003"},
+		"inactive":  {"004", "005"},
+		"suspended": {"006"},
+		"ready":     {"007", "008"},
+		// a subscriber which changed its status while listing appears in two partitions
+		"standby": {"003", "009"},
+	}}
+	ac := newIteratorTestClient(t, s)
+
+	stream := ac.ListSubscribersParallel(context.Background(), &ParallelListOptions{
+		Partitions:  PartitionByStatus(ListSubscribersOptions{Limit: 1}, "active", "inactive", "suspended", "ready", "standby"),
+		Concurrency: 2,
+	})
+	defer stream.Close()
+	var imsis []string
+	for stream.Next() {
+		imsis = append(imsis, stream.Value().IMSI)
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("listing failed: %v", err)
+	}
+	sort.Strings(imsis)
+	if strings.Join(imsis, ",") != "001,002,003,004,005,006,007,008,009" {
+		t.Fatalf("unexpected subscribers: %v", imsis)
+	}
+	if s.maxInFlight > 2 {
+		t.Fatalf("concurrency was not bounded: %d", s.maxInFlight)
+	}
+}
+
+func TestListSubscribersParallelError(t *testing.T) {
+	s := &partitionedServer{subscribers: map[string][]string{
+		"active": {"001", "002", "003", "004", "005", "006"},
+	}, fail: "inactive"}
+	ac := newIteratorTestClient(t, s)
+
+	stream := ac.ListSubscribersParallel(context.Background(), &ParallelListOptions{
+		Partitions: PartitionByStatus(ListSubscribersOptions{}, "active", "inactive"),
+	})
+	defer stream.Close()
+	for stream.Next() {
+	}
+	var apiErr *APIError
+	if err := stream.Err(); err == nil || !errors.As(err, &apiErr) || apiErr.ErrorCode != "SEM0001" {
+		t.Fatalf("expected the API error, got %v", err)
+	}
+}
+
+func TestListSubscribersParallelClose(t *testing.T) {
+	s := &partitionedServer{subscribers: map[string][]string{
+		"active":   {"001", "002", "003", "004", "005", "006"},
+		"inactive": {"007", "008", "009", "010", "011", "012"},
+	}}
+	ac := newIteratorTestClient(t, s)
+
+	stream := ac.ListSubscribersParallel(context.Background(), &ParallelListOptions{
+		Partitions: PartitionByStatus(ListSubscribersOptions{Limit: 1}, "active", "inactive"),
+	})
+	if !stream.Next() {
+		t.Fatalf("Next() returned false: %v", stream.Err())
+	}
+	stream.Close()
+	if stream.Err() != nil {
+		t.Fatalf("closing the stream should not be an error: %v", stream.Err())
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.requests >= 12 {
+		t.Fatalf("listing did not stop early: %d requests", s.requests)
+	}
+}
+
+func TestPartitionByTagValuePrefix(t *testing.T) {
+	partitions := PartitionByTagValuePrefix(ListSubscribersOptions{Limit: 100}, "name", "a", "b")
+	if len(partitions) != 2 {
+		t.Fatalf("unexpected partitions: %+v", partitions)
+	}
+	if q := partitions[1].String(); q != "tag_name=name&tag_value=b&tag_value_match_mode=prefix&limit=100" {
+		t.Fatalf("unexpected query: %s", q)
+	}
+}
@@ -0,0 +1,154 @@
+package soracom
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimiter limits the rate of API calls
+type RateLimiter interface {
+	// Wait blocks until an API call is permitted or ctx is done.
+	Wait(ctx context.Context) error
+}
+
+// TokenBucket is a RateLimiter based on the token bucket algorithm. It is safe for concurrent use.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket creates a TokenBucket which permits ratePerSecond calls per second on average and up to burst calls at once.
+func NewTokenBucket(ratePerSecond float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:   ratePerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is done.
+func (tb *TokenBucket) Wait(ctx context.Context) error {
+	for {
+		d := tb.take(time.Now())
+		if d == 0 {
+			return nil
+		}
+		err := sleepWithContext(ctx, d)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// take consumes a token if available. Otherwise it returns the time until the next token becomes available.
+func (tb *TokenBucket) take(now time.Time) time.Duration {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	if elapsed := now.Sub(tb.last); elapsed > 0 {
+		tb.tokens += elapsed.Seconds() * tb.rate
+		if tb.tokens > tb.burst {
+			tb.tokens = tb.burst
+		}
+		tb.last = now
+	}
+
+	if tb.tokens >= 1 {
+		tb.tokens--
+		return 0
+	}
+	if tb.rate <= 0 {
+		return time.Second
+	}
+	d := time.Duration((1 - tb.tokens) / tb.rate * float64(time.Second))
+	if d <= 0 {
+		d = time.Nanosecond
+	}
+	return d
+}
+
+// EndpointClass is a category of API endpoints which share a rate limit budget
+type EndpointClass string
+
+const (
+	// EndpointClassOther is a class for endpoints which do not belong to any other class
+	EndpointClassOther EndpointClass = "other"
+
+	// EndpointClassStats is a class for endpoints under /v1/stats
+	EndpointClassStats EndpointClass = "stats"
+
+	// EndpointClassSubscriberRead is a class for endpoints to read subscribers
+	EndpointClassSubscriberRead EndpointClass = "subscriberRead"
+
+	// EndpointClassSubscriberWrite is a class for endpoints to modify subscribers
+	EndpointClassSubscriberWrite EndpointClass = "subscriberWrite"
+)
+
+// ClassifyEndpoint returns the EndpointClass for an API call with the specified method and path.
+func ClassifyEndpoint(method, path string) EndpointClass {
+	switch {
+	case strings.HasPrefix(path, "/v1/stats/"):
+		return EndpointClassStats
+	case path == "/v1/subscribers" || strings.HasPrefix(path, "/v1/subscribers/"):
+		if method == http.MethodGet || method == http.MethodHead {
+			return EndpointClassSubscriberRead
+		}
+		return EndpointClassSubscriberWrite
+	}
+	return EndpointClassOther
+}
+
+// RateLimits holds rate limiters applied to API calls.
+// The same RateLimits can be set on multiple APIClients to share the budget among them.
+type RateLimits struct {
+	// Global limits every API call.
+	Global RateLimiter
+
+	// PerClass limits API calls for each EndpointClass in addition to Global.
+	PerClass map[EndpointClass]RateLimiter
+}
+
+func (rl *RateLimits) wait(ctx context.Context, method, path string) error {
+	if rl == nil {
+		return nil
+	}
+	if l, ok := rl.PerClass[ClassifyEndpoint(method, path)]; ok && l != nil {
+		err := l.Wait(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	if rl.Global != nil {
+		return rl.Global.Wait(ctx)
+	}
+	return nil
+}
+
+var (
+	sharedRateLimitsMu sync.Mutex
+	sharedRateLimits   = map[string]*RateLimits{}
+)
+
+// SharedRateLimits returns the RateLimits shared by all callers with the same operatorID.
+// newRateLimits is called to create the RateLimits only when none exists for operatorID yet.
+func SharedRateLimits(operatorID string, newRateLimits func() *RateLimits) *RateLimits {
+	sharedRateLimitsMu.Lock()
+	defer sharedRateLimitsMu.Unlock()
+
+	rl, ok := sharedRateLimits[operatorID]
+	if !ok {
+		rl = newRateLimits()
+		sharedRateLimits[operatorID] = rl
+	}
+	return rl
+}
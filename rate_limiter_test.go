@@ -0,0 +1,141 @@
+package soracom
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type countingRateLimiter struct {
+	mu    sync.Mutex
+	count int
+}
+
+func (l *countingRateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.count++
+	return nil
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	tb := NewTokenBucket(10, 2)
+	tb.last = now
+
+	if d := tb.take(now); d != 0 {
+		t.Fatalf("1st token should be available: %v", d)
+	}
+	if d := tb.take(now); d != 0 {
+		t.Fatalf("2nd token should be available: %v", d)
+	}
+	if d := tb.take(now); d != 100*time.Millisecond {
+		t.Fatalf("expected to wait 100ms, got %v", d)
+	}
+	if d := tb.take(now.Add(100 * time.Millisecond)); d != 0 {
+		t.Fatalf("token should have been refilled: %v", d)
+	}
+	if d := tb.take(now.Add(time.Hour)); d != 0 {
+		t.Fatalf("token should have been refilled: %v", d)
+	}
+	if tb.tokens != 1 {
+		t.Fatalf("tokens should not exceed burst: %v", tb.tokens)
+	}
+}
+
+func TestTokenBucketWait(t *testing.T) {
+	tb := NewTokenBucket(50, 1)
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		err := tb.Wait(context.Background())
+		if err != nil {
+			t.Fatalf("Wait() failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Fatalf("rate limit was not applied: %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	tb = NewTokenBucket(0.001, 1)
+	_ = tb.Wait(ctx)
+	if err := tb.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestClassifyEndpoint(t *testing.T) {
+	testData := []struct {
+		method   string
+		path     string
+		expected EndpointClass
+	}{
+		{"GET", "/v1/stats/air/subscribers/001010000000001?from=0&to=1&period=day", EndpointClassStats},
+		{"POST", "/v1/stats/air/operators/OP0000000000/export", EndpointClassStats},
+		{"GET", "/v1/subscribers", EndpointClassSubscriberRead},
+		{"GET", "/v1/subscribers/001010000000001", EndpointClassSubscriberRead},
+		{"POST", "/v1/subscribers/001010000000001/activate", EndpointClassSubscriberWrite},
+		{"PUT", "/v1/subscribers/001010000000001/tags", EndpointClassSubscriberWrite},
+		{"GET", "/v1/groups", EndpointClassOther},
+		{"POST", "/v1/auth", EndpointClassOther},
+	}
+	for _, data := range testData {
+		if c := ClassifyEndpoint(data.method, data.path); c != data.expected {
+			t.Errorf("ClassifyEndpoint(%s, %s): expected %s, got %s", data.method, data.path, data.expected, c)
+		}
+	}
+}
+
+func TestSharedRateLimits(t *testing.T) {
+	newRateLimits := func() *RateLimits {
+		return &RateLimits{Global: NewTokenBucket(10, 10)}
+	}
+	rl1 := SharedRateLimits("OPTEST0000001", newRateLimits)
+	rl2 := SharedRateLimits("OPTEST0000001", newRateLimits)
+	rl3 := SharedRateLimits("OPTEST0000002", newRateLimits)
+	if rl1 != rl2 {
+		t.Fatal("RateLimits should be shared for the same operator")
+	}
+	if rl1 == rl3 {
+		t.Fatal("RateLimits should not be shared among different operators")
+	}
+}
+
+func TestAPIClientRateLimits(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1/subscribers" {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	global := &countingRateLimiter{}
+	write := &countingRateLimiter{}
+	rl := &RateLimits{
+		Global:   global,
+		PerClass: map[EndpointClass]RateLimiter{EndpointClassSubscriberWrite: write},
+	}
+	ac1 := NewAPIClient(&APIClientOptions{Endpoint: ts.URL, RateLimits: rl})
+	ac2 := NewAPIClient(&APIClientOptions{Endpoint: ts.URL, RateLimits: rl})
+
+	if _, _, err := ac1.ListSubscribers(nil); err != nil {
+		t.Fatalf("ListSubscribers() failed: %v", err)
+	}
+	if _, err := ac2.ActivateSubscriber("001010000000001"); err != nil {
+		t.Fatalf("ActivateSubscriber() failed: %v", err)
+	}
+	if global.count != 2 {
+		t.Fatalf("expected 2 calls through global limiter, got %d", global.count)
+	}
+	if write.count != 1 {
+		t.Fatalf("expected 1 call through subscriber write limiter, got %d", write.count)
+	}
+}
@@ -0,0 +1,175 @@
+package soracom
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy holds parameters to retry API calls which failed with a transient error.
+// Responses with status 429 or 5xx and transient network errors are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first one. Values less than 2 disable retrying.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. The delay is doubled on each subsequent retry.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between attempts. Zero means no cap.
+	// A delay requested by the server with a Retry-After header is honored even if it exceeds MaxDelay.
+	MaxDelay time.Duration
+
+	// Jitter is the fraction (0.0 - 1.0) of each delay that is randomized to avoid synchronized retries.
+	Jitter float64
+
+	// RetryNonIdempotent enables retrying non-idempotent requests such as POST.
+	// By default only GET, HEAD, OPTIONS, PUT and DELETE requests are retried.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy with reasonable default values
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    30 * time.Second,
+		Jitter:      0.5,
+	}
+}
+
+var (
+	jitterMu   sync.Mutex
+	jitterRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func isRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func isTransientNetworkError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
+	}
+	return false
+}
+
+func (p *RetryPolicy) shouldRetry(method string, attempt int, res *http.Response, err error) bool {
+	if p == nil || attempt >= p.MaxAttempts {
+		return false
+	}
+	if !p.RetryNonIdempotent && !isIdempotentMethod(method) {
+		return false
+	}
+	if err != nil {
+		return IsRetryable(err)
+	}
+	return isRetryableStatus(res.StatusCode)
+}
+
+// delay returns the time to wait before the next attempt. attempt is the number of attempts made so far.
+func (p *RetryPolicy) delay(attempt int, res *http.Response) time.Duration {
+	if res != nil {
+		if d, ok := parseRetryAfter(res.Header.Get("Retry-After"), time.Now()); ok {
+			return d
+		}
+	}
+
+	d := float64(p.BaseDelay) * math.Pow(2, float64(attempt-1))
+	if p.MaxDelay > 0 && d > float64(p.MaxDelay) {
+		d = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		jitterMu.Lock()
+		r := jitterRand.Float64()
+		jitterMu.Unlock()
+		d -= d * math.Min(p.Jitter, 1) * r
+	}
+	return time.Duration(d)
+}
+
+// parseRetryAfter parses a value of Retry-After header which is either delay-seconds or an HTTP-date.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(v); err == nil {
+		if s < 0 {
+			return 0, false
+		}
+		return time.Duration(s) * time.Second, true
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	d := t.Sub(now)
+	if d < 0 {
+		d = 0
+	}
+	return d, true
+}
+
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// doWithRetry calls send until it succeeds, fails permanently or the policy gives up.
+// send must build a fresh request on each call so that the request body can be replayed.
+func doWithRetry(ctx context.Context, policy *RetryPolicy, method string, send func() (*http.Response, error)) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		res, err := send()
+		if !policy.shouldRetry(method, attempt, res, err) {
+			return res, err
+		}
+
+		d := policy.delay(attempt, res)
+		if res != nil {
+			_, _ = io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		err = sleepWithContext(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
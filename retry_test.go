@@ -0,0 +1,193 @@
+package soracom
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func fastRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    5 * time.Millisecond,
+		Jitter:      0.5,
+	}
+}
+
+func newFlakyServer(t *testing.T, failures int32, status int, bodies chan<- string) (*httptest.Server, *int32) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		if bodies != nil {
+			b, _ := io.ReadAll(r.Body)
+			bodies <- string(b)
+		}
+		if n <= failures {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"code":"ERR0001","message":"try again"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"imsi":"001010000000001"}`))
+	}))
+	t.Cleanup(ts.Close)
+	return ts, &calls
+}
+
+func TestRetryIdempotentRequest(t *testing.T) {
+	ts, calls := newFlakyServer(t, 2, http.StatusServiceUnavailable, nil)
+	ac := NewAPIClient(&APIClientOptions{Endpoint: ts.URL, RetryPolicy: fastRetryPolicy()})
+
+	sub, err := ac.GetSubscriber("001010000000001")
+	if err != nil {
+		t.Fatalf("GetSubscriber() failed: %v", err)
+	}
+	if sub.IMSI != "001010000000001" {
+		t.Fatalf("unexpected imsi: %v", sub.IMSI)
+	}
+	if *calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", *calls)
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	ts, calls := newFlakyServer(t, 10, http.StatusTooManyRequests, nil)
+	ac := NewAPIClient(&APIClientOptions{Endpoint: ts.URL, RetryPolicy: fastRetryPolicy()})
+
+	_, err := ac.GetSubscriber("001010000000001")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected APIError with status 429, got %v", err)
+	}
+	if *calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", *calls)
+	}
+}
+
+func TestRetryDoesNotRetryClientErrors(t *testing.T) {
+	ts, calls := newFlakyServer(t, 1, http.StatusBadRequest, nil)
+	ac := NewAPIClient(&APIClientOptions{Endpoint: ts.URL, RetryPolicy: fastRetryPolicy()})
+
+	_, err := ac.GetSubscriber("001010000000001")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if *calls != 1 {
+		t.Fatalf("expected 1 attempt, got %d", *calls)
+	}
+}
+
+func TestRetryNonIdempotentRequest(t *testing.T) {
+	t.Run("not retried by default", func(t *testing.T) {
+		ts, calls := newFlakyServer(t, 1, http.StatusServiceUnavailable, nil)
+		ac := NewAPIClient(&APIClientOptions{Endpoint: ts.URL, RetryPolicy: fastRetryPolicy()})
+
+		_, err := ac.ActivateSubscriber("001010000000001")
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if *calls != 1 {
+			t.Fatalf("expected 1 attempt, got %d", *calls)
+		}
+	})
+
+	t.Run("retried when opted in with the same body", func(t *testing.T) {
+		bodies := make(chan string, 3)
+		ts, calls := newFlakyServer(t, 1, http.StatusServiceUnavailable, bodies)
+		p := fastRetryPolicy()
+		p.RetryNonIdempotent = true
+		ac := NewAPIClient(&APIClientOptions{Endpoint: ts.URL, RetryPolicy: p})
+
+		_, err := ac.SetSubscriberGroup("001010000000001", "group-1")
+		if err != nil {
+			t.Fatalf("SetSubscriberGroup() failed: %v", err)
+		}
+		if *calls != 2 {
+			t.Fatalf("expected 2 attempts, got %d", *calls)
+		}
+		first, second := <-bodies, <-bodies
+		if first != second || first != `{"groupId":"group-1"}` {
+			t.Fatalf("request body was not replayed: %q, %q", first, second)
+		}
+	})
+}
+
+func TestRetryMetadataClient(t *testing.T) {
+	ts, calls := newFlakyServer(t, 1, http.StatusBadGateway, nil)
+	mc := NewMetadataClient(&MetadataClientOptions{Endpoint: ts.URL, RetryPolicy: fastRetryPolicy()})
+
+	_, err := mc.GetSubscriber()
+	if err != nil {
+		t.Fatalf("GetSubscriber() failed: %v", err)
+	}
+	if *calls != 2 {
+		t.Fatalf("expected 2 attempts, got %d", *calls)
+	}
+}
+
+func TestRetryStopsWhenContextIsCanceled(t *testing.T) {
+	ts, _ := newFlakyServer(t, 10, http.StatusServiceUnavailable, nil)
+	p := fastRetryPolicy()
+	p.BaseDelay = time.Hour
+	p.MaxDelay = 0
+	ac := NewAPIClient(&APIClientOptions{Endpoint: ts.URL, RetryPolicy: p})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := ac.GetSubscriberWithContext(ctx, "001010000000001")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	p := &RetryPolicy{MaxAttempts: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second}
+	for i, e := range expected {
+		if d := p.delay(i+1, nil); d != e {
+			t.Fatalf("attempt %d: expected %v, got %v", i+1, e, d)
+		}
+	}
+
+	p.Jitter = 1
+	for i := 1; i < 5; i++ {
+		if d := p.delay(i, nil); d < 0 || d > time.Second {
+			t.Fatalf("attempt %d: delay out of range: %v", i, d)
+		}
+	}
+
+	res := &http.Response{Header: http.Header{}}
+	res.Header.Set("Retry-After", "7")
+	if d := p.delay(1, res); d != 7*time.Second {
+		t.Fatalf("Retry-After was not honored: %v", d)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	testData := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"-1", 0, false},
+		{"Wed, 01 Jan 2020 00:00:10 GMT", 10 * time.Second, true},
+		{"Tue, 31 Dec 2019 23:59:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+	for _, data := range testData {
+		d, ok := parseRetryAfter(data.value, now)
+		if d != data.expected || ok != data.ok {
+			t.Errorf("parseRetryAfter(%q): expected (%v, %v), got (%v, %v)", data.value, data.expected, data.ok, d, ok)
+		}
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"net/http/httputil"
 	"net/url"
 	"strconv"
 	"strings"
@@ -67,6 +66,9 @@ type AuthRequest struct {
 	Password            string `json:"password,omitempty"`
 	AuthKeyID           string `json:"authKeyId,omitempty"`
 	AuthKey             string `json:"authKey,omitempty"`
+	OperatorID          string `json:"operatorId,omitempty"`
+	UserName            string `json:"userName,omitempty"`
+	MFAOTPCode          string `json:"mfaOTPCode,omitempty"`
 	TokenTimeoutSeconds int    `json:"tokenTimeoutSeconds"`
 }
 
@@ -90,14 +92,27 @@ func (ak *AuthKey) JSON() string {
 type AuthResponse struct {
 	APIKey     string `json:"apiKey"`
 	OperatorID string `json:"operatorId"`
+	UserName   string `json:"userName"`
 	Token      string `json:"token"`
 }
 
-func parseAuthResponse(resp *http.Response) *AuthResponse {
+type switchUserRequest struct {
+	OperatorID          string `json:"operatorId"`
+	UserName            string `json:"userName"`
+	TokenTimeoutSeconds int    `json:"tokenTimeoutSeconds"`
+}
+
+func (r *switchUserRequest) JSON() string {
+	return toJSON(r)
+}
+
+func parseAuthResponse(resp *http.Response) (*AuthResponse, error) {
 	var ar AuthResponse
-	dec := json.NewDecoder(resp.Body)
-	_ = dec.Decode(&ar)
-	return &ar
+	err := decodeObject(resp.Body, &ar)
+	if err != nil {
+		return nil, err
+	}
+	return &ar, nil
 }
 
 // InitOperatorForSandboxRequest represents the request body of InitOperatorForSandbox.
@@ -136,11 +151,13 @@ type GenerateAPITokenResponse struct {
 	Token string `json:"token"`
 }
 
-func parseGenerateAPITokenResponse(resp *http.Response) *GenerateAPITokenResponse {
+func parseGenerateAPITokenResponse(resp *http.Response) (*GenerateAPITokenResponse, error) {
 	var r GenerateAPITokenResponse
-	dec := json.NewDecoder(resp.Body)
-	_ = dec.Decode(&r)
-	return &r
+	err := decodeObject(resp.Body, &r)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
 }
 
 type updatePasswordRequest struct {
@@ -157,11 +174,13 @@ type GetSupportTokenResponse struct {
 	Token string `json:"token"`
 }
 
-func parseGetSupportTokenResponse(resp *http.Response) *GetSupportTokenResponse {
+func parseGetSupportTokenResponse(resp *http.Response) (*GetSupportTokenResponse, error) {
 	var r GetSupportTokenResponse
-	dec := json.NewDecoder(resp.Body)
-	_ = dec.Decode(&r)
-	return &r
+	err := decodeObject(resp.Body, &r)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
 }
 
 // CreateOperatorRequest defines the email, password, and coverage type(s) of the operator to be created
@@ -194,11 +213,13 @@ type Operator struct {
 	UpdateDate     *time.Time `json:"updateDate"`
 }
 
-func parseOperator(resp *http.Response) *Operator {
+func parseOperator(resp *http.Response) (*Operator, error) {
 	var o Operator
-	dec := json.NewDecoder(resp.Body)
-	_ = dec.Decode(&o)
-	return &o
+	err := decodeObject(resp.Body, &o)
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
 }
 
 // TagValueMatchMode is one of MatchModeUnspecified, MatchModeExact or MatchModePrefix
@@ -319,97 +340,102 @@ type SessionStatus struct {
 
 // Subscriber keeps information about a subscriber
 type Subscriber struct {
-	APN                string          `json:"apn"`
-	CreatedAt          *TimestampMilli `json:"createdAt"`
-	ExpiredAt          *TimestampMilli `json:"expiredAt"`
-	ExpiryAction       *string         `json:"expiryAction,omitempty"`
-	GroupID            *string         `json:"groupId,omitempty"`
-	ICCID              string          `json:"iccid,omitempty"`
-	IMEILock           *IMEILock       `json:"imeiLock,omitempty"`
-	IMSI               string          `json:"imsi"`
-	IPAddress          *string         `json:"ipAddress,omitempty"`
-	LastModifiedAt     *TimestampMilli `json:"lastModifiedAt"`
-	ModuleType         string          `json:"ModuleType"`
-	MSISDN             string          `json:"msisdn"`
-	OperatorID         string          `json:"operatorId"`
-	Plan               int             `json:"plan"`
-	SerialNumber       string          `json:"serialNumber"`
-	SessionStatus      *SessionStatus  `json:"sessionStatus"`
-	SpeedClass         string          `json:"speedClass"`
-	Status             string          `json:"status"`
-	Tags               Tags            `json:"tags"`
-	TerminationEnabled bool            `json:"terminationEnabled"`
+	APN                string           `json:"apn"`
+	CreatedAt          *TimestampMilli  `json:"createdAt"`
+	ExpiredAt          *TimestampMilli  `json:"expiredAt"`
+	ExpiryAction       *string          `json:"expiryAction,omitempty"`
+	GroupID            *string          `json:"groupId,omitempty"`
+	ICCID              string           `json:"iccid,omitempty"`
+	IMEILock           *IMEILock        `json:"imeiLock,omitempty"`
+	IMSI               string           `json:"imsi"`
+	IPAddress          *string          `json:"ipAddress,omitempty"`
+	LastModifiedAt     *TimestampMilli  `json:"lastModifiedAt"`
+	ModuleType         string           `json:"ModuleType"`
+	MSISDN             string           `json:"msisdn"`
+	OperatorID         string           `json:"operatorId"`
+	Plan               int              `json:"plan"`
+	SerialNumber       string           `json:"serialNumber"`
+	SessionStatus      *SessionStatus   `json:"sessionStatus"`
+	SpeedClass         SpeedClass       `json:"speedClass"`
+	Status             SubscriberStatus `json:"status"`
+	Subscription       string           `json:"subscription,omitempty"`
+	Tags               Tags             `json:"tags"`
+	TerminationEnabled bool             `json:"terminationEnabled"`
 }
 
 // PaginationKeys holds keys for pagination
 type PaginationKeys struct {
 	Prev string
 	Next string
+
+	// PrevURL and NextURL are the URLs of the previous and the next pages as they are in the Link header
+	PrevURL string
+	NextURL string
 }
 
 func parseLinkHeader(linkHeader string) *PaginationKeys {
-	var pk *PaginationKeys
-	if linkHeader != "" {
-		pk = &PaginationKeys{}
-		links := strings.Split(linkHeader, ",")
-		for _, link := range links {
-			s := strings.Split(link, ";")
-			u, err := url.Parse(strings.Trim(s[0], "<>"))
-			if err != nil {
-				continue
-			}
-			lek := u.Query()["last_evaluated_key"][0]
-			rel := strings.Split(s[1], "=")[1]
-			if rel == "prev" {
-				pk.Prev = lek
-			} else if rel == "next" {
-				pk.Next = lek
+	if strings.TrimSpace(linkHeader) == "" {
+		return nil
+	}
+	pk := &PaginationKeys{}
+	for _, l := range parseLinks(linkHeader) {
+		for _, rel := range strings.Fields(strings.ToLower(l.params["rel"])) {
+			switch rel {
+			case "prev", "previous":
+				if pk.PrevURL == "" {
+					pk.PrevURL = l.url
+					pk.Prev = lastEvaluatedKey(l.url)
+				}
+			case "next":
+				if pk.NextURL == "" {
+					pk.NextURL = l.url
+					pk.Next = lastEvaluatedKey(l.url)
+				}
 			}
 		}
 	}
 	return pk
 }
 
-func parseListSubscribersResponse(resp *http.Response) ([]Subscriber, *PaginationKeys, error) {
-	subs := make([]Subscriber, 0, 10)
-	dec := json.NewDecoder(resp.Body)
-
-	// read open bracket
-	_, err := dec.Token()
+func lastEvaluatedKey(u string) string {
+	parsed, err := url.Parse(u)
 	if err != nil {
-		return nil, nil, err
+		return ""
 	}
+	return parsed.Query().Get("last_evaluated_key")
+}
 
-	for dec.More() {
-		var s Subscriber
-		err = dec.Decode(&s)
-		if err != nil {
-			continue
+func parseListSubscribersResponse(resp *http.Response, lenient bool) ([]Subscriber, *PaginationKeys, error) {
+	subs := make([]Subscriber, 0, 10)
+	err := decodeList(resp.Body, lenient, func(raw json.RawMessage) error {
+		var v Subscriber
+		err := json.Unmarshal(raw, &v)
+		if err == nil {
+			subs = append(subs, v)
 		}
-		subs = append(subs, s)
-	}
-
-	// read close bracket
-	_, err = dec.Token()
-	if err != nil {
+		return err
+	})
+	if _, ok := err.(DecodeErrors); err != nil && !ok {
 		return nil, nil, err
 	}
 
 	linkHeader := resp.Header.Get("Link")
 	pk := parseLinkHeader(linkHeader)
 
-	return subs, pk, nil
+	return subs, pk, err
 }
 
-func parseSubscriber(resp *http.Response) *Subscriber {
+func parseSubscriber(resp *http.Response) (*Subscriber, error) {
 	var sub Subscriber
-	dec := json.NewDecoder(resp.Body)
-	_ = dec.Decode(&sub)
-	return &sub
+	err := decodeObject(resp.Body, &sub)
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
 }
 
 type updateSpeedClassRequest struct {
-	SpeedClass string `json:"speedClass"`
+	SpeedClass SpeedClass `json:"speedClass"`
 }
 
 // JSON retunrs a JSON representing updateSpeedClassRequest object
@@ -434,6 +460,46 @@ func (r *setExpiredAtRequest) JSON() string {
 	return string(bodyBytes)
 }
 
+type setIMEILockRequest struct {
+	IMEI string `json:"imei,omitempty"`
+}
+
+// JSON retunrs a JSON representing setIMEILockRequest object
+func (r *setIMEILockRequest) JSON() string {
+	bodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return ""
+	}
+	return string(bodyBytes)
+}
+
+type sendSMSRequest struct {
+	EncodingType SMSEncoding `json:"encodingType"`
+	Payload      string      `json:"payload"`
+}
+
+// JSON retunrs a JSON representing sendSMSRequest object
+func (r *sendSMSRequest) JSON() string {
+	bodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return ""
+	}
+	return string(bodyBytes)
+}
+
+type sendSMSResponse struct {
+	MessageID string `json:"messageId"`
+}
+
+func parseSendSMSResponse(resp *http.Response) (string, error) {
+	var r sendSMSResponse
+	err := decodeObject(resp.Body, &r)
+	if err != nil {
+		return "", err
+	}
+	return r.MessageID, nil
+}
+
 type setSubscriberGroupRequest struct {
 	GroupID string `json:"groupId"`
 }
@@ -512,6 +578,21 @@ const (
 
 	// SpeedClassS14xFast is s1.4xfast
 	SpeedClassS14xFast SpeedClass = "s1.4xfast"
+
+	// SpeedClassS18xFast is s1.8xfast
+	SpeedClassS18xFast SpeedClass = "s1.8xfast"
+
+	// SpeedClassT1Standard is t1.standard
+	SpeedClassT1Standard SpeedClass = "t1.standard"
+
+	// SpeedClassU1Standard is u1.standard
+	SpeedClassU1Standard SpeedClass = "u1.standard"
+
+	// SpeedClassU1Slow is u1.slow
+	SpeedClassU1Slow SpeedClass = "u1.slow"
+
+	// SpeedClassArcStandard is arc.standard
+	SpeedClassArcStandard SpeedClass = "arc.standard"
 )
 
 // AirStatsForSpeedClass holds Upload/Download Bytes/Packets for a speed class
@@ -529,11 +610,21 @@ type AirStats struct {
 	Traffic  map[SpeedClass]AirStatsForSpeedClass `json:"dataTrafficStatsMap"`
 }
 
-func parseAirStats(resp *http.Response) []AirStats {
-	var v []AirStats
-	dec := json.NewDecoder(resp.Body)
-	_ = dec.Decode(&v)
-	return v
+func parseAirStats(resp *http.Response, lenient bool) ([]AirStats, error) {
+	airStats := make([]AirStats, 0, 10)
+	err := decodeList(resp.Body, lenient, func(raw json.RawMessage) error {
+		var v AirStats
+		err := json.Unmarshal(raw, &v)
+		if err == nil {
+			airStats = append(airStats, v)
+		}
+		return err
+	})
+	if _, ok := err.(DecodeErrors); err != nil && !ok {
+		return nil, err
+	}
+
+	return airStats, err
 }
 
 // JSON retunrs a JSON representing AirStats object
@@ -581,11 +672,21 @@ type BeamStats struct {
 	Traffic  map[BeamType]BeamStatsForType `json:"beamStatsMap"`
 }
 
-func parseBeamStats(resp *http.Response) []BeamStats {
-	var v []BeamStats
-	dec := json.NewDecoder(resp.Body)
-	_ = dec.Decode(&v)
-	return v
+func parseBeamStats(resp *http.Response, lenient bool) ([]BeamStats, error) {
+	beamStats := make([]BeamStats, 0, 10)
+	err := decodeList(resp.Body, lenient, func(raw json.RawMessage) error {
+		var v BeamStats
+		err := json.Unmarshal(raw, &v)
+		if err == nil {
+			beamStats = append(beamStats, v)
+		}
+		return err
+	})
+	if _, ok := err.(DecodeErrors); err != nil && !ok {
+		return nil, err
+	}
+
+	return beamStats, err
 }
 
 // JSON retunrs a JSON representing BeamStats object
@@ -607,11 +708,13 @@ type exportAirStatsResponse struct {
 	URL string `json:"url"`
 }
 
-func parseExportAirStatsResponse(resp *http.Response) *exportAirStatsResponse {
+func parseExportAirStatsResponse(resp *http.Response) (*exportAirStatsResponse, error) {
 	var r exportAirStatsResponse
-	dec := json.NewDecoder(resp.Body)
-	_ = dec.Decode(&r)
-	return &r
+	err := decodeObject(resp.Body, &r)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
 }
 
 type exportBeamStatsRequest struct {
@@ -628,11 +731,13 @@ type exportBeamStatsResponse struct {
 	URL string `json:"url"`
 }
 
-func parseExportBeamStatsResponse(resp *http.Response) *exportBeamStatsResponse {
+func parseExportBeamStatsResponse(resp *http.Response) (*exportBeamStatsResponse, error) {
 	var r exportBeamStatsResponse
-	dec := json.NewDecoder(resp.Body)
-	_ = dec.Decode(&r)
-	return &r
+	err := decodeObject(resp.Body, &r)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
 }
 
 // ConfigNamespace is a type of namespace of a configuration
@@ -677,35 +782,24 @@ func (lso *ListGroupsOptions) String() string {
 	return strings.Join(s, "&")
 }
 
-func parseListGroupsResponse(resp *http.Response) ([]Group, *PaginationKeys, error) {
+func parseListGroupsResponse(resp *http.Response, lenient bool) ([]Group, *PaginationKeys, error) {
 	groups := make([]Group, 0, 10)
-	dec := json.NewDecoder(resp.Body)
-
-	// read open bracket
-	_, err := dec.Token()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	for dec.More() {
-		var g Group
-		err = dec.Decode(&g)
-		if err != nil {
-			continue
+	err := decodeList(resp.Body, lenient, func(raw json.RawMessage) error {
+		var v Group
+		err := json.Unmarshal(raw, &v)
+		if err == nil {
+			groups = append(groups, v)
 		}
-		groups = append(groups, g)
-	}
-
-	// read close bracket
-	_, err = dec.Token()
-	if err != nil {
+		return err
+	})
+	if _, ok := err.(DecodeErrors); err != nil && !ok {
 		return nil, nil, err
 	}
 
 	linkHeader := resp.Header.Get("Link")
 	pk := parseLinkHeader(linkHeader)
 
-	return groups, pk, nil
+	return groups, pk, err
 }
 
 type createGroupRequest struct {
@@ -716,11 +810,13 @@ func (r *createGroupRequest) JSON() string {
 	return toJSON(r)
 }
 
-func parseGroup(resp *http.Response) *Group {
+func parseGroup(resp *http.Response) (*Group, error) {
 	var g Group
-	dec := json.NewDecoder(resp.Body)
-	_ = dec.Decode(&g)
-	return &g
+	err := decodeObject(resp.Body, &g)
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
 }
 
 // ListSubscribersInGroupOptions holds options for APIClient.ListSubscribersInGroup()
@@ -751,6 +847,9 @@ type MetaData struct {
 	Enabled     bool   `json:"enabled"`
 	ReadOnly    bool   `json:"readonly"`
 	AllowOrigin string `json:"allowOrigin"`
+
+	// Extra holds keys not known to this SDK
+	Extra map[string]interface{} `json:"-"`
 }
 
 // AirConfig holds configuration parameters for SORACOM Air
@@ -761,18 +860,14 @@ type AirConfig struct {
 	UserData            string   `json:"userdata"`
 	BinaryParserEnabled bool     `json:"binaryParserEnabled"`
 	BinaryParserFormat  string   `json:"binaryParserFormat"`
+
+	// Extra holds keys not known to this SDK
+	Extra map[string]interface{} `json:"-"`
 }
 
 // JSON converts AirConfig into JSON string
 func (ac *AirConfig) JSON() string {
-	return toJSON([]GroupConfig{
-		{Key: "useCustomDns", Value: ac.UseCustomDNS},
-		{Key: "dnsServers", Value: ac.DNSServers},
-		{Key: "metadata", Value: ac.MetaData},
-		{Key: "userdata", Value: ac.UserData},
-		{Key: "binaryParserEnabled", Value: ac.BinaryParserEnabled},
-		{Key: "binaryParserFormat", Value: ac.BinaryParserFormat},
-	})
+	return toJSON(ac.GroupConfigs())
 }
 
 // CustomHeader holds Action, Key and Value for a custom header
@@ -832,11 +927,37 @@ type BeamHTTPConfig struct {
 	PSK                 string                  `json:"psk"`
 }
 
+// BeamWebsiteConfig holds SORACOM Beam website entry point configurations
+type BeamWebsiteConfig struct {
+	Name                string                  `json:"name"`
+	Destination         string                  `json:"destination"`
+	Enabled             bool                    `json:"enabled"`
+	AddSubscriberHeader bool                    `json:"addSubscriberHeader"`
+	CustomHeaders       map[string]CustomHeader `json:"customHeaders"`
+	Username            string                  `json:"username"`
+	Password            string                  `json:"password"`
+}
+
+// BeamWebhookConfig holds SORACOM Beam webhook entry point configurations
+type BeamWebhookConfig struct {
+	Name                string                  `json:"name"`
+	Destination         string                  `json:"destination"`
+	Enabled             bool                    `json:"enabled"`
+	AddSubscriberHeader bool                    `json:"addSubscriberHeader"`
+	AddSignature        bool                    `json:"addSignature"`
+	CustomHeaders       map[string]CustomHeader `json:"customHeaders"`
+	PSK                 string                  `json:"psk"`
+	ContentType         string                  `json:"contentType"`
+}
+
 // FunnelDestinationConfig holds SORACOM Funnel Destination configurations
 type FunnelDestinationConfig struct {
 	Provider    string `json:"provider"`
 	Service     string `json:"service"`
 	ResourceUrl string `json:"resourceUrl"`
+
+	// Extra holds keys not known to this SDK
+	Extra map[string]interface{} `json:"-"`
 }
 
 // EventHandlerRuleType is a type of event hander's rule
@@ -941,32 +1062,21 @@ func (leho *ListEventHandlersOptions) String() string {
 	return strings.Join(s, "&")
 }
 
-func parseListEventHandlersResponse(resp *http.Response) ([]EventHandler, error) {
+func parseListEventHandlersResponse(resp *http.Response, lenient bool) ([]EventHandler, error) {
 	eventHandlers := make([]EventHandler, 0, 10)
-	dec := json.NewDecoder(resp.Body)
-
-	// read open bracket
-	_, err := dec.Token()
-	if err != nil {
-		return nil, err
-	}
-
-	for dec.More() {
-		var eh EventHandler
-		err = dec.Decode(&eh)
-		if err != nil {
-			continue
+	err := decodeList(resp.Body, lenient, func(raw json.RawMessage) error {
+		var v EventHandler
+		err := json.Unmarshal(raw, &v)
+		if err == nil {
+			eventHandlers = append(eventHandlers, v)
 		}
-		eventHandlers = append(eventHandlers, eh)
-	}
-
-	// read close bracket
-	_, err = dec.Token()
-	if err != nil {
+		return err
+	})
+	if _, ok := err.(DecodeErrors); err != nil && !ok {
 		return nil, err
 	}
 
-	return eventHandlers, nil
+	return eventHandlers, err
 }
 
 func parseEventHandler(resp *http.Response) (*EventHandler, error) {
@@ -1166,17 +1276,23 @@ type SessionEvent struct {
 	PrimaryIMSI string    `json:"primaryImsi"`
 }
 
-func parseListSessionEvents(resp *http.Response) ([]SessionEvent, *PaginationKeys, error) {
-	var events []SessionEvent
-
-	dec := json.NewDecoder(resp.Body)
-	err := dec.Decode(&events)
-	if err != nil {
+func parseListSessionEvents(resp *http.Response, lenient bool) ([]SessionEvent, *PaginationKeys, error) {
+	events := make([]SessionEvent, 0, 10)
+	err := decodeList(resp.Body, lenient, func(raw json.RawMessage) error {
+		var v SessionEvent
+		err := json.Unmarshal(raw, &v)
+		if err == nil {
+			events = append(events, v)
+		}
+		return err
+	})
+	if _, ok := err.(DecodeErrors); err != nil && !ok {
 		return nil, nil, err
 	}
 
 	linkHeader := resp.Header.Get("Link")
 	pk := parseLinkHeader(linkHeader)
+
 	return events, pk, err
 }
 
@@ -1215,21 +1331,3 @@ func toJSON(x interface{}) string {
 	}
 	return string(bodyBytes)
 }
-
-func dumpHTTPRequest(req *http.Request) {
-	dump, err := httputil.DumpRequest(req, true)
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
-	fmt.Println(string(dump))
-}
-
-func dumpHTTPResponse(resp *http.Response) {
-	dump, err := httputil.DumpResponse(resp, true)
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
-	fmt.Println(string(dump))
-}
@@ -97,7 +97,7 @@ func TestMarshals(t *testing.T) {
 		response := &http.Response{
 			Body: io.NopCloser(b),
 		}
-		rs, pek, err := parseListSessionEvents(response)
+		rs, pek, err := parseListSessionEvents(response, false)
 		if err != nil {
 			t.Fatalf("failed to parseListSessionEvents(): %s", err)
 		}
@@ -0,0 +1,120 @@
+package soracom
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// SMSEncoding is the encoding of an SMS message
+type SMSEncoding int
+
+const (
+	// SMSEncodingAuto selects SMSEncodingGSM7 if the text can be encoded with it, SMSEncodingUCS2 otherwise
+	SMSEncodingAuto SMSEncoding = 0
+
+	// SMSEncodingGSM7 is the GSM 7-bit default alphabet
+	SMSEncodingGSM7 SMSEncoding = 1
+
+	// SMSEncodingUCS2 is UCS-2
+	SMSEncodingUCS2 SMSEncoding = 2
+)
+
+func (e SMSEncoding) String() string {
+	switch e {
+	case SMSEncodingGSM7:
+		return "GSM7"
+	case SMSEncodingUCS2:
+		return "UCS-2"
+	}
+	return "auto"
+}
+
+// Lengths of an SMS message. Text longer than a single message is split into segments with a header, which makes each segment shorter.
+const (
+	smsGSM7SingleLength  = 160
+	smsGSM7SegmentLength = 153
+	smsUCS2SingleLength  = 70
+	smsUCS2SegmentLength = 67
+)
+
+// maxSMSSegments is the number of segments the API can send at once
+const maxSMSSegments = 1
+
+var (
+	// ErrSMSEmpty is returned if an SMS message has no text
+	ErrSMSEmpty = errors.New("SMS message is empty")
+
+	// ErrSMSTooLong is returned if an SMS message does not fit in the segments the API can send
+	ErrSMSTooLong = errors.New("SMS message is too long")
+
+	// ErrSMSNotEncodable is returned if text contains characters which cannot be encoded with SMSEncodingGSM7
+	ErrSMSNotEncodable = errors.New("SMS message cannot be encoded with GSM 7-bit default alphabet")
+)
+
+// gsm7Basic is the GSM 03.38 default alphabet except the escape character
+const gsm7Basic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+// gsm7Extension is the GSM 03.38 extension table. Each character takes two septets.
+const gsm7Extension = "\f^{}\\[~]|€"
+
+// gsm7Length returns the number of septets needed to encode text with the GSM 7-bit default alphabet
+func gsm7Length(text string) (int, bool) {
+	n := 0
+	for _, r := range text {
+		switch {
+		case strings.ContainsRune(gsm7Basic, r):
+			n++
+		case strings.ContainsRune(gsm7Extension, r):
+			n += 2
+		default:
+			return 0, false
+		}
+	}
+	return n, true
+}
+
+func smsSegments(length, single, segment int) int {
+	if length <= single {
+		return 1
+	}
+	return (length + segment - 1) / segment
+}
+
+// SMSSegments returns the number of segments text is split into when encoded with encoding, and the encoding actually used.
+// SMSEncodingAuto is resolved to SMSEncodingGSM7 or SMSEncodingUCS2.
+func SMSSegments(text string, encoding SMSEncoding) (int, SMSEncoding, error) {
+	if text == "" {
+		return 0, encoding, ErrSMSEmpty
+	}
+
+	switch encoding {
+	case SMSEncodingAuto, SMSEncodingGSM7:
+		n, ok := gsm7Length(text)
+		if ok {
+			return smsSegments(n, smsGSM7SingleLength, smsGSM7SegmentLength), SMSEncodingGSM7, nil
+		}
+		if encoding == SMSEncodingGSM7 {
+			return 0, encoding, ErrSMSNotEncodable
+		}
+		fallthrough
+	case SMSEncodingUCS2:
+		n := len(utf16.Encode([]rune(text)))
+		return smsSegments(n, smsUCS2SingleLength, smsUCS2SegmentLength), SMSEncodingUCS2, nil
+	}
+	return 0, encoding, fmt.Errorf("unknown SMS encoding: %d", encoding)
+}
+
+// validateSMS checks that text can be sent as an SMS message and returns the encoding to send it with
+func validateSMS(text string, encoding SMSEncoding) (SMSEncoding, error) {
+	segments, encoding, err := SMSSegments(text, encoding)
+	if err != nil {
+		return encoding, err
+	}
+	if segments > maxSMSSegments {
+		return encoding, fmt.Errorf("%w: %d segments with %s encoding, up to %d allowed", ErrSMSTooLong, segments, encoding, maxSMSSegments)
+	}
+	return encoding, nil
+}
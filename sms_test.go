@@ -0,0 +1,93 @@
+package soracom
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSMSSegments(t *testing.T) {
+	var testData = []struct {
+		Name     string
+		Text     string
+		Encoding SMSEncoding
+		Segments int
+		Used     SMSEncoding
+		Err      error
+	}{
+		{"empty", "", SMSEncodingAuto, 0, SMSEncodingAuto, ErrSMSEmpty},
+		{"ascii", "hello", SMSEncodingAuto, 1, SMSEncodingGSM7, nil},
+		{"gsm7 full", strings.Repeat("a", 160), SMSEncodingGSM7, 1, SMSEncodingGSM7, nil},
+		{"gsm7 two segments", strings.Repeat("a", 161), SMSEncodingGSM7, 2, SMSEncodingGSM7, nil},
+		{"gsm7 extension", strings.Repeat("€", 80), SMSEncodingGSM7, 1, SMSEncodingGSM7, nil},
+		{"gsm7 extension overflow", strings.Repeat("€", 81), SMSEncodingGSM7, 2, SMSEncodingGSM7, nil},
+		{"gsm7 three segments", strings.Repeat("a", 307), SMSEncodingGSM7, 3, SMSEncodingGSM7, nil},
+		{"gsm7 not encodable", "こんにちは", SMSEncodingGSM7, 0, SMSEncodingGSM7, ErrSMSNotEncodable},
+		{"auto falls back to ucs2", "こんにちは", SMSEncodingAuto, 1, SMSEncodingUCS2, nil},
+		{"ucs2 full", strings.Repeat("あ", 70), SMSEncodingUCS2, 1, SMSEncodingUCS2, nil},
+		{"ucs2 two segments", strings.Repeat("あ", 71), SMSEncodingUCS2, 2, SMSEncodingUCS2, nil},
+		{"ucs2 surrogate pairs", strings.Repeat("😀", 35), SMSEncodingUCS2, 1, SMSEncodingUCS2, nil},
+		{"ucs2 ascii", "hello", SMSEncodingUCS2, 1, SMSEncodingUCS2, nil},
+	}
+
+	for _, data := range testData {
+		data := data
+		t.Run(data.Name, func(t *testing.T) {
+			segments, used, err := SMSSegments(data.Text, data.Encoding)
+			if !errors.Is(err, data.Err) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if segments != data.Segments || used != data.Used {
+				t.Fatalf("unexpected result: %d segments with %s", segments, used)
+			}
+		})
+	}
+
+	_, _, err := SMSSegments("hello", SMSEncoding(3))
+	if err == nil {
+		t.Fatalf("unknown encoding should be an error")
+	}
+}
+
+func TestSendSMS(t *testing.T) {
+	var paths []string
+	var reqs []sendSMSRequest
+	ac := newIteratorTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req sendSMSRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		paths = append(paths, r.URL.Path)
+		reqs = append(reqs, req)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"messageId":"msg-` + string(rune('0'+len(reqs))) + `"}`))
+	}))
+
+	id, err := ac.SendSMSToSubscriber("001010000000001", "hello", SMSEncodingAuto)
+	if err != nil || id != "msg-1" {
+		t.Fatalf("SendSMSToSubscriber() failed: %q, %v", id, err)
+	}
+	id, err = ac.SendSMSToMSISDN("810000000001", "こんにちは", SMSEncodingAuto)
+	if err != nil || id != "msg-2" {
+		t.Fatalf("SendSMSToMSISDN() failed: %q, %v", id, err)
+	}
+	if paths[0] != "/v1/subscribers/001010000000001/send_sms" || paths[1] != "/v1/subscribers/msisdn/810000000001/send_sms" {
+		t.Fatalf("unexpected paths: %v", paths)
+	}
+	if reqs[0].EncodingType != SMSEncodingGSM7 || reqs[0].Payload != "hello" || reqs[1].EncodingType != SMSEncodingUCS2 {
+		t.Fatalf("unexpected requests: %+v", reqs)
+	}
+
+	_, err = ac.SendSMSToSubscriber("001010000000001", strings.Repeat("a", 161), SMSEncodingGSM7)
+	if !errors.Is(err, ErrSMSTooLong) {
+		t.Fatalf("expected ErrSMSTooLong: %v", err)
+	}
+	_, err = ac.SendSMSToMSISDN("810000000001", "", SMSEncodingAuto)
+	if !errors.Is(err, ErrSMSEmpty) {
+		t.Fatalf("expected ErrSMSEmpty: %v", err)
+	}
+	if len(reqs) != 2 {
+		t.Fatalf("invalid messages should not be sent: %+v", reqs)
+	}
+}
@@ -0,0 +1,96 @@
+package soracom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupportedSpeedClass is returned if a speed class is not available for the subscription of a subscriber
+var ErrUnsupportedSpeedClass = errors.New("speed class is not available for the subscription")
+
+var s1SpeedClasses = []SpeedClass{
+	SpeedClassS1Minimum,
+	SpeedClassS1Slow,
+	SpeedClassS1Standard,
+	SpeedClassS1Fast,
+	SpeedClassS14xFast,
+	SpeedClassS18xFast,
+}
+
+// speedClassesBySubscription is the catalogue of speed classes available for each subscription
+var speedClassesBySubscription = map[string][]SpeedClass{
+	"plan01s":     s1SpeedClasses,
+	"planX1":      s1SpeedClasses,
+	"planX2":      s1SpeedClasses,
+	"planX3":      s1SpeedClasses,
+	"planP1":      s1SpeedClasses,
+	"plan01s-LDV": {SpeedClassS1Minimum, SpeedClassS1Slow},
+	"plan-D":      s1SpeedClasses[:5],
+	"plan-K":      s1SpeedClasses[:5],
+	"plan-US":     s1SpeedClasses[:5],
+	"plan-KM1":    {SpeedClassT1Standard},
+	"plan-DU":     {SpeedClassU1Standard, SpeedClassU1Slow},
+	"planArc01":   {SpeedClassArcStandard},
+}
+
+// SpeedClassesFor returns the speed classes available for the subscription, e.g. "plan01s".
+// ok is false if the subscription is not in the catalogue of the SDK.
+func SpeedClassesFor(subscription string) (speedClasses []SpeedClass, ok bool) {
+	scs, ok := speedClassesBySubscription[subscription]
+	if !ok {
+		return nil, false
+	}
+	return append([]SpeedClass(nil), scs...), true
+}
+
+// ValidateSpeedClass returns an error matching ErrUnsupportedSpeedClass if the speed class is not available for the subscription.
+// Subscriptions not in the catalogue of the SDK are not validated.
+func ValidateSpeedClass(subscription string, speedClass SpeedClass) error {
+	scs, ok := speedClassesBySubscription[subscription]
+	if !ok {
+		return nil
+	}
+	for _, sc := range scs {
+		if sc == speedClass {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s for %s", ErrUnsupportedSpeedClass, speedClass, subscription)
+}
+
+// AvailableSpeedClasses returns the speed classes available for the subscription of the subscriber, or nil if it is unknown
+func (s *Subscriber) AvailableSpeedClasses() []SpeedClass {
+	scs, _ := SpeedClassesFor(s.Subscription)
+	return scs
+}
+
+// CanUseSpeedClass reports whether the speed class is available for the subscription of the subscriber.
+// It returns true if the subscription is unknown.
+func (s *Subscriber) CanUseSpeedClass(speedClass SpeedClass) bool {
+	return ValidateSpeedClass(s.Subscription, speedClass) == nil
+}
+
+// validateSpeedClass checks the speed class against the subscription of the subscriber, if the client validates speed classes
+func (ac *APIClient) validateSpeedClass(ctx context.Context, imsi string, speedClass SpeedClass) error {
+	if !ac.validateSpeedClasses {
+		return nil
+	}
+	sub, err := ac.GetSubscriberWithContext(ctx, imsi)
+	if err != nil {
+		return err
+	}
+	return ValidateSpeedClass(sub.Subscription, speedClass)
+}
+
+// validateSpeedClass checks the speed class against the subscription of the calling subscriber, if the client validates speed classes
+func (mc *MetadataClient) validateSpeedClass(ctx context.Context, speedClass SpeedClass) error {
+	if !mc.validateSpeedClasses {
+		return nil
+	}
+	sub, err := mc.GetSubscriberWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	return ValidateSpeedClass(sub.Subscription, speedClass)
+}
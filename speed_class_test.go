@@ -0,0 +1,75 @@
+package soracom
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestValidateSpeedClass(t *testing.T) {
+	testData := []struct {
+		subscription string
+		speedClass   SpeedClass
+		valid        bool
+	}{
+		{"plan01s", SpeedClassS18xFast, true},
+		{"plan01s", SpeedClassU1Standard, false},
+		{"plan-D", SpeedClassS14xFast, true},
+		{"plan-D", SpeedClassS18xFast, false},
+		{"plan-KM1", SpeedClassT1Standard, true},
+		{"plan-KM1", SpeedClassS1Standard, false},
+		{"plan-DU", SpeedClassU1Slow, true},
+		{"planArc01", SpeedClassArcStandard, true},
+		{"plan-unknown", "x1.unknown", true},
+	}
+	for _, data := range testData {
+		err := ValidateSpeedClass(data.subscription, data.speedClass)
+		if (err == nil) != data.valid || (err != nil && !errors.Is(err, ErrUnsupportedSpeedClass)) {
+			t.Errorf("ValidateSpeedClass(%s, %s): unexpected result %v", data.subscription, data.speedClass, err)
+		}
+		sub := &Subscriber{Subscription: data.subscription}
+		if sub.CanUseSpeedClass(data.speedClass) != data.valid {
+			t.Errorf("CanUseSpeedClass(%s) for %s: expected %v", data.speedClass, data.subscription, data.valid)
+		}
+	}
+
+	scs, ok := SpeedClassesFor("plan-DU")
+	if !ok || len(scs) != 2 {
+		t.Fatalf("unexpected speed classes: %v", scs)
+	}
+	scs[0] = "modified"
+	if (&Subscriber{Subscription: "plan-DU"}).AvailableSpeedClasses()[0] != SpeedClassU1Standard {
+		t.Fatal("catalogue should not be modified through returned speed classes")
+	}
+}
+
+func TestUpdateSubscriberSpeedClassValidation(t *testing.T) {
+	updated := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			updated = true
+		}
+		_, _ = w.Write([]byte(`{"imsi":"001010000000001","subscription":"plan-KM1","speedClass":"t1.standard"}`))
+	}))
+	defer ts.Close()
+
+	ac := NewAPIClient(&APIClientOptions{Endpoint: ts.URL, ValidateSpeedClasses: true})
+	ac.SetAuthInfo("api-key", "token", "OP0000000000")
+	_, err := ac.UpdateSubscriberSpeedClass("001010000000001", SpeedClassS1Fast)
+	if !errors.Is(err, ErrUnsupportedSpeedClass) || updated {
+		t.Fatalf("expected ErrUnsupportedSpeedClass without calling the API, got %v", err)
+	}
+	sub, err := ac.UpdateSubscriberSpeedClass("001010000000001", SpeedClassT1Standard)
+	if err != nil || !updated || sub.SpeedClass != SpeedClassT1Standard {
+		t.Fatalf("UpdateSubscriberSpeedClass() failed: %+v, %v", sub, err)
+	}
+
+	updated = false
+	mc := NewMetadataClient(&MetadataClientOptions{Endpoint: ts.URL, ValidateSpeedClasses: true})
+	_, err = mc.UpdateSpeedClass(SpeedClassU1Slow)
+	if !errors.Is(err, ErrUnsupportedSpeedClass) || updated {
+		t.Fatalf("expected ErrUnsupportedSpeedClass without calling the API, got %v", err)
+	}
+}
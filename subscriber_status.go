@@ -0,0 +1,143 @@
+package soracom
+
+import (
+	"context"
+	"fmt"
+)
+
+// SubscriberStatus is the status of a subscriber
+type SubscriberStatus string
+
+// Subscriber statuses
+const (
+	SubscriberStatusInStock    SubscriberStatus = "instock"
+	SubscriberStatusShipped    SubscriberStatus = "shipped"
+	SubscriberStatusReady      SubscriberStatus = "ready"
+	SubscriberStatusActive     SubscriberStatus = "active"
+	SubscriberStatusInactive   SubscriberStatus = "inactive"
+	SubscriberStatusStandby    SubscriberStatus = "standby"
+	SubscriberStatusSuspended  SubscriberStatus = "suspended"
+	SubscriberStatusTerminated SubscriberStatus = "terminated"
+)
+
+// subscriberTransitions is the graph of status changes which can be made with the API
+var subscriberTransitions = map[SubscriberStatus][]SubscriberStatus{
+	SubscriberStatusReady:     {SubscriberStatusActive, SubscriberStatusInactive, SubscriberStatusStandby, SubscriberStatusSuspended, SubscriberStatusTerminated},
+	SubscriberStatusActive:    {SubscriberStatusInactive, SubscriberStatusSuspended, SubscriberStatusTerminated},
+	SubscriberStatusInactive:  {SubscriberStatusActive, SubscriberStatusSuspended, SubscriberStatusTerminated},
+	SubscriberStatusStandby:   {SubscriberStatusActive, SubscriberStatusInactive, SubscriberStatusSuspended, SubscriberStatusTerminated},
+	SubscriberStatusSuspended: {SubscriberStatusActive, SubscriberStatusInactive, SubscriberStatusTerminated},
+}
+
+// CanTransition reports whether a subscriber in status from can be changed to status to by a single API call.
+// Changing a status to itself is always allowed, as the API does nothing.
+func CanTransition(from, to SubscriberStatus) bool {
+	if from == to {
+		return true
+	}
+	for _, s := range subscriberTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionAction is an API call which changes the status of a subscriber
+type TransitionAction string
+
+// Transition actions named after the APIClient methods
+const (
+	TransitionActionActivate          TransitionAction = "ActivateSubscriber"
+	TransitionActionDeactivate        TransitionAction = "DeactivateSubscriber"
+	TransitionActionSetToStandby      TransitionAction = "SetToStandby"
+	TransitionActionSuspend           TransitionAction = "Suspend"
+	TransitionActionEnableTermination TransitionAction = "EnableSubscriberTermination"
+	TransitionActionTerminate         TransitionAction = "TerminateSubscriber"
+)
+
+var transitionActions = map[SubscriberStatus]TransitionAction{
+	SubscriberStatusActive:     TransitionActionActivate,
+	SubscriberStatusInactive:   TransitionActionDeactivate,
+	SubscriberStatusStandby:    TransitionActionSetToStandby,
+	SubscriberStatusSuspended:  TransitionActionSuspend,
+	SubscriberStatusTerminated: TransitionActionTerminate,
+}
+
+func invalidTransition(from, to SubscriberStatus) error {
+	return fmt.Errorf("%w: subscriber cannot change from %q to %q", ErrInvalidStateTransition, from, to)
+}
+
+// PlanTransition returns the API calls needed to change the status of sub to to, in the order they must be made.
+// Termination is preceded by EnableSubscriberTermination if termination is disabled for sub.
+// The plan is empty if sub is already in status to. The error matches ErrInvalidStateTransition if the change is not possible.
+func PlanTransition(sub *Subscriber, to SubscriberStatus) ([]TransitionAction, error) {
+	if sub.Status == to {
+		return nil, nil
+	}
+	if !CanTransition(sub.Status, to) {
+		return nil, invalidTransition(sub.Status, to)
+	}
+
+	var plan []TransitionAction
+	if to == SubscriberStatusTerminated && !sub.TerminationEnabled {
+		plan = append(plan, TransitionActionEnableTermination)
+	}
+	return append(plan, transitionActions[to]), nil
+}
+
+// TransitionSubscriber changes the status of a subscriber by making all API calls planned by PlanTransition
+func (ac *APIClient) TransitionSubscriber(imsi string, to SubscriberStatus) (*Subscriber, error) {
+	return ac.TransitionSubscriberWithContext(context.Background(), imsi, to)
+}
+
+// TransitionSubscriberWithContext is the context-aware version of TransitionSubscriber.
+func (ac *APIClient) TransitionSubscriberWithContext(ctx context.Context, imsi string, to SubscriberStatus) (*Subscriber, error) {
+	sub, err := ac.GetSubscriberWithContext(ctx, imsi)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := PlanTransition(sub, to)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, action := range plan {
+		switch action {
+		case TransitionActionActivate:
+			sub, err = ac.activateSubscriber(ctx, imsi)
+		case TransitionActionDeactivate:
+			sub, err = ac.deactivateSubscriber(ctx, imsi)
+		case TransitionActionSetToStandby:
+			sub, err = ac.setToStandby(ctx, imsi)
+		case TransitionActionSuspend:
+			sub, err = ac.suspend(ctx, imsi)
+		case TransitionActionEnableTermination:
+			sub, err = ac.EnableSubscriberTerminationWithContext(ctx, imsi)
+		case TransitionActionTerminate:
+			sub, err = ac.terminateSubscriber(ctx, imsi)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sub, nil
+}
+
+// validateTransition checks that the subscriber can be changed to status to, if the client validates transitions
+func (ac *APIClient) validateTransition(ctx context.Context, imsi string, to SubscriberStatus) error {
+	if !ac.validateTransitions {
+		return nil
+	}
+	sub, err := ac.GetSubscriberWithContext(ctx, imsi)
+	if err != nil {
+		return err
+	}
+	if !CanTransition(sub.Status, to) {
+		return invalidTransition(sub.Status, to)
+	}
+	if to == SubscriberStatusTerminated && sub.Status != to && !sub.TerminationEnabled {
+		return fmt.Errorf("%w: termination is disabled for subscriber %s", ErrInvalidStateTransition, imsi)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package soracom
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	testData := []struct {
+		from, to SubscriberStatus
+		expected bool
+	}{
+		{SubscriberStatusReady, SubscriberStatusActive, true},
+		{SubscriberStatusActive, SubscriberStatusInactive, true},
+		{SubscriberStatusInactive, SubscriberStatusActive, true},
+		{SubscriberStatusSuspended, SubscriberStatusTerminated, true},
+		{SubscriberStatusActive, SubscriberStatusActive, true},
+		{SubscriberStatusActive, SubscriberStatusReady, false},
+		{SubscriberStatusActive, SubscriberStatusStandby, false},
+		{SubscriberStatusTerminated, SubscriberStatusActive, false},
+		{SubscriberStatusShipped, SubscriberStatusActive, false},
+	}
+	for _, data := range testData {
+		if CanTransition(data.from, data.to) != data.expected {
+			t.Errorf("CanTransition(%s, %s): expected %v", data.from, data.to, data.expected)
+		}
+	}
+}
+
+func TestPlanTransition(t *testing.T) {
+	plan, err := PlanTransition(&Subscriber{Status: SubscriberStatusActive}, SubscriberStatusTerminated)
+	if err != nil || len(plan) != 2 || plan[0] != TransitionActionEnableTermination || plan[1] != TransitionActionTerminate {
+		t.Fatalf("unexpected plan: %v, %v", plan, err)
+	}
+	plan, err = PlanTransition(&Subscriber{Status: SubscriberStatusActive, TerminationEnabled: true}, SubscriberStatusTerminated)
+	if err != nil || len(plan) != 1 || plan[0] != TransitionActionTerminate {
+		t.Fatalf("unexpected plan: %v, %v", plan, err)
+	}
+	plan, err = PlanTransition(&Subscriber{Status: SubscriberStatusInactive}, SubscriberStatusInactive)
+	if err != nil || len(plan) != 0 {
+		t.Fatalf("unexpected plan: %v, %v", plan, err)
+	}
+	_, err = PlanTransition(&Subscriber{Status: SubscriberStatusTerminated}, SubscriberStatusActive)
+	if !errors.Is(err, ErrInvalidStateTransition) {
+		t.Fatalf("expected ErrInvalidStateTransition, got %v", err)
+	}
+}
+
+// fakeSubscriberServer keeps the status of a subscriber and records status changing calls
+type fakeSubscriberServer struct {
+	mu    sync.Mutex
+	sub   Subscriber
+	calls []string
+}
+
+func (s *fakeSubscriberServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method == http.MethodPost {
+		action := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		s.calls = append(s.calls, action)
+		switch action {
+		case "activate":
+			s.sub.Status = SubscriberStatusActive
+		case "deactivate":
+			s.sub.Status = SubscriberStatusInactive
+		case "suspend":
+			s.sub.Status = SubscriberStatusSuspended
+		case "set_to_standby":
+			s.sub.Status = SubscriberStatusStandby
+		case "enable_termination":
+			s.sub.TerminationEnabled = true
+		case "terminate":
+			s.sub.Status = SubscriberStatusTerminated
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(&s.sub)
+}
+
+func TestTransitionSubscriber(t *testing.T) {
+	s := &fakeSubscriberServer{sub: Subscriber{IMSI: "001010000000001", Status: SubscriberStatusActive}}
+	ac := newIteratorTestClient(t, s)
+
+	sub, err := ac.TransitionSubscriber("001010000000001", SubscriberStatusTerminated)
+	if err != nil {
+		t.Fatalf("TransitionSubscriber() failed: %v", err)
+	}
+	if sub.Status != SubscriberStatusTerminated || strings.Join(s.calls, ",") != "enable_termination,terminate" {
+		t.Fatalf("unexpected result: %s, %v", sub.Status, s.calls)
+	}
+
+	_, err = ac.TransitionSubscriber("001010000000001", SubscriberStatusActive)
+	if !errors.Is(err, ErrInvalidStateTransition) || len(s.calls) != 2 {
+		t.Fatalf("expected ErrInvalidStateTransition without calling the API, got %v, %v", err, s.calls)
+	}
+}
+
+func TestValidateTransitions(t *testing.T) {
+	s := &fakeSubscriberServer{sub: Subscriber{IMSI: "001010000000001", Status: SubscriberStatusActive}}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+	ac := NewAPIClient(&APIClientOptions{Endpoint: ts.URL, ValidateTransitions: true})
+	ac.SetAuthInfo("api-key", "token", "OP0000000000")
+
+	_, err := ac.SetToStandby("001010000000001")
+	if !errors.Is(err, ErrInvalidStateTransition) {
+		t.Fatalf("expected ErrInvalidStateTransition, got %v", err)
+	}
+	_, err = ac.TerminateSubscriber("001010000000001")
+	if !errors.Is(err, ErrInvalidStateTransition) {
+		t.Fatalf("termination should be refused while it is disabled, got %v", err)
+	}
+	if len(s.calls) != 0 {
+		t.Fatalf("API should not have been called: %v", s.calls)
+	}
+
+	sub, err := ac.Suspend("001010000000001")
+	if err != nil || sub.Status != SubscriberStatusSuspended {
+		t.Fatalf("Suspend() failed: %+v, %v", sub, err)
+	}
+}
@@ -0,0 +1,106 @@
+package soracom
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const authPath = "/v1/auth"
+
+// canReauth reports whether the client remembers how it authenticated and thus can authenticate again by itself.
+func (ac *APIClient) canReauth() bool {
+	return ac.creds.load().authRequest != nil
+}
+
+func isAuthError(err error) bool {
+	return errors.Is(err, ErrAuthFailed)
+}
+
+// refreshTokenIfNeeded refreshes the API token if it expires within the refresh margin.
+// An error is returned only if the token could not be refreshed and has already expired.
+func (ac *APIClient) refreshTokenIfNeeded(ctx context.Context) error {
+	s := ac.creds.load()
+	if s.authRequest == nil && s.expiresAt.IsZero() {
+		// the token has been set by SetAuthInfo; its lifetime is unknown
+		return nil
+	}
+	if time.Until(s.expiresAt) >= ac.tokenRefreshMargin {
+		return nil
+	}
+
+	err := ac.refreshToken(ctx, s.token)
+	if err != nil && time.Now().After(s.expiresAt) {
+		return err
+	}
+	return nil
+}
+
+// refreshToken renews the API token with GenerateAPIToken, or authenticates again if that fails.
+// staleToken is the token which the caller found to be expiring; nothing is done if another goroutine has already replaced it.
+func (ac *APIClient) refreshToken(ctx context.Context, staleToken string) error {
+	ac.authMu.Lock()
+	defer ac.authMu.Unlock()
+
+	s := ac.creds.load()
+	if s.token != staleToken {
+		return nil
+	}
+
+	if time.Now().Before(s.expiresAt) {
+		params := &apiParams{
+			method:      "POST",
+			path:        "/v1/operators/" + s.operatorID + "/token",
+			contentType: "application/json",
+			body:        (&generateAPITokenRequest{Timeout: int(ac.tokenTimeout / time.Second)}).JSON(),
+		}
+		issuedAt := time.Now()
+		resp, err := ac.doCallAPI(ctx, params)
+		if err == nil {
+			defer resp.Body.Close()
+			respBody, err := parseGenerateAPITokenResponse(resp)
+			if err == nil && respBody.Token != "" {
+				ac.creds.update(func(s authState) authState {
+					s.token = respBody.Token
+					s.expiresAt = issuedAt.Add(ac.tokenTimeout)
+					return s
+				})
+				return nil
+			}
+		}
+	}
+
+	return ac.authAgain(ctx)
+}
+
+// reauth authenticates again with the remembered credentials after staleToken has been rejected by the server.
+func (ac *APIClient) reauth(ctx context.Context, staleToken string) error {
+	ac.authMu.Lock()
+	defer ac.authMu.Unlock()
+
+	if ac.creds.load().token != staleToken {
+		return nil
+	}
+
+	return ac.authAgain(ctx)
+}
+
+// authAgain calls the auth API with the remembered credentials, and switches the user again if the client had switched.
+// The caller must hold authMu.
+func (ac *APIClient) authAgain(ctx context.Context) error {
+	s := ac.creds.load()
+	if s.authRequest == nil {
+		return errors.New("no credentials to authenticate again with")
+	}
+	body := *s.authRequest
+	err := ac.auth(ctx, &body)
+	if err != nil {
+		return err
+	}
+
+	if s.switchUser != nil {
+		su := *s.switchUser
+		return ac.switchUser(ctx, &su)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package soracom
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeAuthServer issues a new token on every /v1/auth or token generation call and accepts only the latest one.
+type fakeAuthServer struct {
+	mu             sync.Mutex
+	currentToken   string
+	authCount      int
+	generateCount  int
+	lastAuthBody   AuthRequest
+	rejectedTokens []string
+}
+
+func (s *fakeAuthServer) issueToken() string {
+	s.currentToken = fmt.Sprintf("token-%d", s.authCount+s.generateCount)
+	return s.currentToken
+}
+
+func (s *fakeAuthServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Path {
+	case "/v1/auth":
+		s.authCount++
+		_ = json.NewDecoder(r.Body).Decode(&s.lastAuthBody)
+		_, _ = w.Write([]byte(toJSON(&AuthResponse{APIKey: "api-key", OperatorID: "OP0000000000", Token: s.issueToken()})))
+	case "/v1/operators/OP0000000000/token":
+		if r.Header.Get("X-Soracom-Token") != s.currentToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"code":"AUM0001","message":"invalid token"}`))
+			return
+		}
+		s.generateCount++
+		_, _ = w.Write([]byte(toJSON(&GenerateAPITokenResponse{Token: s.issueToken()})))
+	default:
+		if r.Header.Get("X-Soracom-Token") != s.currentToken {
+			s.rejectedTokens = append(s.rejectedTokens, r.Header.Get("X-Soracom-Token"))
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"code":"AUM0001","message":"invalid token"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"imsi":"001010000000001"}`))
+	}
+}
+
+func (s *fakeAuthServer) expireToken() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.currentToken = "expired"
+}
+
+func TestReauthOnExpiredToken(t *testing.T) {
+	s := &fakeAuthServer{}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	ac := NewAPIClient(&APIClientOptions{Endpoint: ts.URL, TokenTimeout: time.Hour})
+	err := ac.AuthWithAuthKey("keyId-xxx", "secret-xxx")
+	if err != nil {
+		t.Fatalf("AuthWithAuthKey() failed: %v", err)
+	}
+	if s.lastAuthBody.TokenTimeoutSeconds != 3600 {
+		t.Fatalf("unexpected token timeout: %d", s.lastAuthBody.TokenTimeoutSeconds)
+	}
+
+	s.expireToken()
+	_, err = ac.GetSubscriber("001010000000001")
+	if err != nil {
+		t.Fatalf("GetSubscriber() failed: %v", err)
+	}
+	if s.authCount != 2 {
+		t.Fatalf("expected 2 auth calls, got %d", s.authCount)
+	}
+	if s.lastAuthBody.AuthKeyID != "keyId-xxx" || s.lastAuthBody.AuthKey != "secret-xxx" {
+		t.Fatalf("remembered credentials were not used: %+v", s.lastAuthBody)
+	}
+}
+
+func TestNoReauthWithoutRememberedCredentials(t *testing.T) {
+	s := &fakeAuthServer{currentToken: "valid"}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	ac := NewAPIClient(&APIClientOptions{Endpoint: ts.URL})
+	ac.SetAuthInfo("api-key", "stale", "OP0000000000")
+
+	_, err := ac.GetSubscriber("001010000000001")
+	if !isAuthError(err) {
+		t.Fatalf("expected an auth error, got %v", err)
+	}
+	if s.authCount != 0 {
+		t.Fatalf("expected no auth calls, got %d", s.authCount)
+	}
+}
+
+func TestProactiveTokenRefresh(t *testing.T) {
+	s := &fakeAuthServer{}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	ac := NewAPIClient(&APIClientOptions{
+		Endpoint:           ts.URL,
+		TokenTimeout:       time.Minute,
+		TokenRefreshMargin: 2 * time.Minute,
+	})
+	err := ac.Auth("test@example.com", "password")
+	if err != nil {
+		t.Fatalf("Auth() failed: %v", err)
+	}
+
+	_, err = ac.GetSubscriber("001010000000001")
+	if err != nil {
+		t.Fatalf("GetSubscriber() failed: %v", err)
+	}
+	if s.generateCount != 1 {
+		t.Fatalf("expected the token to be refreshed once, got %d", s.generateCount)
+	}
+	if s.authCount != 1 {
+		t.Fatalf("expected 1 auth call, got %d", s.authCount)
+	}
+	if len(s.rejectedTokens) != 0 {
+		t.Fatalf("stale tokens were used: %v", s.rejectedTokens)
+	}
+}
+
+func TestConcurrentReauth(t *testing.T) {
+	s := &fakeAuthServer{}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	ac := NewAPIClient(&APIClientOptions{Endpoint: ts.URL})
+	err := ac.Auth("test@example.com", "password")
+	if err != nil {
+		t.Fatalf("Auth() failed: %v", err)
+	}
+	s.expireToken()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := ac.GetSubscriber("001010000000001")
+			if err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("GetSubscriber() failed: %v", err)
+	}
+	if s.authCount != 2 {
+		t.Fatalf("expected exactly one re-authentication, got %d auth calls", s.authCount)
+	}
+}